@@ -89,3 +89,118 @@ func columnsOverlap(columns []column) bool {
 	}
 	return false
 }
+
+// Layout of an aligned table guessed by InferLayout
+type Layout struct {
+	// NbColumn is the number of columns found in the table
+	NbColumn int
+	// Confidence is the share of non-empty lines (between 0 and 1) whose fields fit the
+	// layout: each field falls into exactly one column and no column gets two fields
+	Confidence float64
+
+	cols []column
+}
+
+// InferLayout guesses columns of an aligned table without knowing their number upfront.
+// Candidate layouts come from the whitespace gutters shared by all lines and from every
+// number of fields seen in the input; the one which fits the most lines wins, ties are
+// resolved in favour of more columns. It's an error when no layout fits any line.
+func InferLayout(lines []string) (Layout, error) {
+	var nonEmpty [][]field
+	counts := map[int]bool{}
+	for _, line := range lines {
+		fields := extractFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		nonEmpty = append(nonEmpty, fields)
+		counts[len(fields)] = true
+	}
+	if len(nonEmpty) == 0 {
+		return Layout{}, errors.New("can't infer columns from empty input")
+	}
+	candidates := [][]column{gutterColumns(nonEmpty)}
+	for nbColumn := range counts {
+		cols, err := columns(lines, nbColumn)
+		if err == nil {
+			candidates = append(candidates, cols)
+		}
+	}
+	var best Layout
+	for _, cols := range candidates {
+		fitting := 0
+		for _, fields := range nonEmpty {
+			if fieldsFit(fields, cols) {
+				fitting++
+			}
+		}
+		confidence := float64(fitting) / float64(len(nonEmpty))
+		if confidence > best.Confidence ||
+			(confidence == best.Confidence && len(cols) > best.NbColumn) {
+			best = Layout{NbColumn: len(cols), Confidence: confidence, cols: cols}
+		}
+	}
+	if best.Confidence == 0 {
+		return Layout{}, errors.New("can't find any plausible column layout")
+	}
+	return best, nil
+}
+
+// gutterColumns returns columns separated by cells which are blank in every line. Lines
+// containing a single field (titles, notes) are ignored unless there is nothing else.
+func gutterColumns(lines [][]field) []column {
+	var rows [][]field
+	for _, fields := range lines {
+		if len(fields) > 1 {
+			rows = append(rows, fields)
+		}
+	}
+	if len(rows) == 0 {
+		rows = lines
+	}
+	var occupied []bool
+	for _, fields := range rows {
+		for _, f := range fields {
+			for len(occupied) < f.To() {
+				occupied = append(occupied, false)
+			}
+			for c := f.from; c < f.To(); c++ {
+				occupied[c] = true
+			}
+		}
+	}
+	var result []column
+	for c, o := range occupied {
+		switch {
+		case !o:
+		case c > 0 && occupied[c-1]:
+			result[len(result)-1].to = c + 1
+		default:
+			result = append(result, column{from: c, to: c + 1})
+		}
+	}
+	return result
+}
+
+// fieldsFit checks whether each field intersects exactly one column and each column
+// contains at most one field
+func fieldsFit(fields []field, cols []column) bool {
+	used := make([]bool, len(cols))
+	for _, f := range fields {
+		found := -1
+		for i, c := range cols {
+			if !c.intersects(f.from, f.To()) {
+				continue
+			}
+			if found >= 0 {
+				return false
+			}
+			found = i
+		}
+		if found < 0 || used[found] {
+			return false
+		}
+		used[found] = true
+	}
+	return true
+}
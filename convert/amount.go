@@ -0,0 +1,170 @@
+package convert
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Locale defines how numbers are written
+type Locale struct {
+	// Decimal separates the fraction
+	Decimal rune
+	// Group contains accepted thousand separators, numbers without them are accepted too
+	Group string
+}
+
+// Common locales
+var (
+	// Plain numbers have decimal point and no thousand separators
+	Plain = Locale{Decimal: '.'}
+	// English numbers look like 1,234.56
+	English = Locale{Decimal: '.', Group: ","}
+	// German numbers look like 1.234,56
+	German = Locale{Decimal: ',', Group: "."}
+	// French numbers look like 1 234,56 (with a space or a no-break space)
+	French = Locale{Decimal: ',', Group: " \u00a0\u202f"}
+	// Swiss numbers look like 1'234.56
+	Swiss = Locale{Decimal: '.', Group: "'’"}
+)
+
+var locales = map[string]Locale{
+	"":   Plain,
+	"en": English,
+	"de": German,
+	"fr": French,
+	"ch": Swiss,
+}
+
+// LocaleByName returns one of the common locales by its name: en, de, fr or ch. Empty
+// name is the Plain locale.
+func LocaleByName(name string) (Locale, bool) {
+	l, ok := locales[strings.ToLower(name)]
+	return l, ok
+}
+
+// currencySymbols maps symbols to ISO 4217 codes, longer symbols go first
+var currencySymbols = []struct{ symbol, code string }{
+	{"US$", "USD"},
+	{"Fr.", "CHF"},
+	{"zł", "PLN"},
+	{"Kč", "CZK"},
+	{"€", "EUR"},
+	{"$", "USD"},
+	{"£", "GBP"},
+	{"¥", "JPY"},
+	{"₺", "TRY"},
+}
+
+// Amount is a decimal with optional ISO 4217 currency code
+type Amount struct {
+	Value    Decimal
+	Currency string
+}
+
+// String formats amount like -1234.56 EUR
+func (a Amount) String() string {
+	if a.Currency == "" {
+		return a.Value.String()
+	}
+	return a.Value.String() + " " + a.Currency
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, text is parsed by ParseAmount
+// with the Plain locale
+func (a *Amount) UnmarshalText(text []byte) error {
+	parsed, err := ParseAmount(string(text), Plain)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// ParseAmount parses amount written in the locale. The number may be preceded or
+// followed by a currency symbol or ISO code ("CHF 12'000.00", "1.234,56 €"), negative
+// amounts have leading or trailing minus ("1 234,56-") or are enclosed in accounting
+// parentheses ("(1,234.56)").
+func ParseAmount(s string, l Locale) (Amount, error) {
+	var a Amount
+	rest := strings.TrimSpace(s)
+	neg, signs := false, 0
+	if strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")") {
+		neg, rest = true, strings.TrimSpace(rest[1:len(rest)-1])
+		signs++
+	}
+	for changed := true; changed; {
+		changed = false
+		if currency, n := leadingCurrency(rest); n > 0 && a.Currency == "" {
+			a.Currency, rest, changed = currency, rest[n:], true
+		}
+		if currency, n := trailingCurrency(rest); n > 0 && a.Currency == "" {
+			a.Currency, rest, changed = currency, rest[:len(rest)-n], true
+		}
+		for _, minus := range []string{"-", "−"} {
+			if strings.HasPrefix(rest, minus) {
+				neg, rest, changed = !neg, rest[len(minus):], true
+				signs++
+			}
+			if strings.HasSuffix(rest, minus) {
+				neg, rest, changed = !neg, rest[:len(rest)-len(minus)], true
+				signs++
+			}
+		}
+		if strings.HasPrefix(rest, "+") {
+			rest, changed = rest[1:], true
+			signs++
+		}
+		rest = strings.TrimFunc(rest, unicode.IsSpace)
+	}
+	if signs > 1 {
+		return Amount{}, errors.Errorf("can't parse amount %q: more than one sign", s)
+	}
+	value, err := parseNumber(rest, l)
+	if err != nil {
+		return Amount{}, errors.Errorf("can't parse amount %q", s)
+	}
+	if neg {
+		value = value.Neg()
+	}
+	a.Value = value
+	return a, nil
+}
+
+// leadingCurrency returns currency at the beginning of s and its length in bytes
+func leadingCurrency(s string) (string, int) {
+	for _, c := range currencySymbols {
+		if strings.HasPrefix(s, c.symbol) {
+			return c.code, len(c.symbol)
+		}
+	}
+	n := len(s)
+	if n >= 3 && isCurrencyCode(s[:3]) && (n == 3 || !unicode.IsLetter(rune(s[3]))) {
+		return s[:3], 3
+	}
+	return "", 0
+}
+
+// trailingCurrency returns currency at the end of s and its length in bytes
+func trailingCurrency(s string) (string, int) {
+	for _, c := range currencySymbols {
+		if strings.HasSuffix(s, c.symbol) {
+			return c.code, len(c.symbol)
+		}
+	}
+	n := len(s)
+	if n >= 3 && isCurrencyCode(s[n-3:]) && (n == 3 || !unicode.IsLetter(rune(s[n-4]))) {
+		return s[n-3:], 3
+	}
+	return "", 0
+}
+
+func isCurrencyCode(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
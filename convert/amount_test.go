@@ -0,0 +1,83 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type amountSuite struct{ suite.Suite }
+
+func TestAmount(t *testing.T) { suite.Run(t, new(amountSuite)) }
+
+func (s *amountSuite) TestParseAmount() {
+	testcases := []struct {
+		input  string
+		locale Locale
+		result string
+	}{
+		{"1.234,56 €", German, "1234.56 EUR"},
+		{"(1,234.56)", English, "-1234.56"},
+		{"1 234,56-", French, "-1234.56"},
+		{"1 234 567,8", French, "1234567.8"},
+		{"CHF 12'000.00", Swiss, "12000.00 CHF"},
+		{"12’000.5 Fr.", Swiss, "12000.5 CHF"},
+		{"-$1,000", English, "-1000 USD"},
+		{"US$ -0.50", English, "-0.50 USD"},
+		{"EUR1234", Plain, "1234 EUR"},
+		{"+12.30 GBP", Plain, "12.30 GBP"},
+		{"(£ 7)", English, "-7 GBP"},
+		{"−3,5", German, "-3.5"},
+		{",5", German, "0.5"},
+		{"  42  ", Plain, "42"},
+	}
+	for _, tc := range testcases {
+		a, err := ParseAmount(tc.input, tc.locale)
+		require.Nil(s.T(), err, tc.input)
+		require.Equal(s.T(), tc.result, a.String(), tc.input)
+	}
+}
+
+func (s *amountSuite) TestParseInvalidAmount() {
+	testcases := []struct {
+		input  string
+		locale Locale
+	}{
+		{"1,234.56", German},
+		{"1.234,56", English},
+		{"1,234.56", Plain},
+		{"12,34,567", English},
+		{"1,2345", English},
+		{",123", English},
+		{"(-5)", English},
+		{"--5", Plain},
+		{"5 EUR USD", Plain},
+		{"EURO 5", Plain},
+		{"", Plain},
+		{"€", Plain},
+		{"1.2.3", Plain},
+	}
+	for _, tc := range testcases {
+		_, err := ParseAmount(tc.input, tc.locale)
+		require.NotNil(s.T(), err, tc.input)
+	}
+}
+
+func (s *amountSuite) TestLocaleByName() {
+	l, ok := LocaleByName("DE")
+	require.True(s.T(), ok)
+	require.Equal(s.T(), German, l)
+	l, ok = LocaleByName("")
+	require.True(s.T(), ok)
+	require.Equal(s.T(), Plain, l)
+	_, ok = LocaleByName("xx")
+	require.False(s.T(), ok)
+}
+
+func (s *amountSuite) TestUnmarshalText() {
+	var a Amount
+	require.Nil(s.T(), a.UnmarshalText([]byte("12.50 EUR")))
+	require.Equal(s.T(), Amount{Value: NewDecimal(1250, 2), Currency: "EUR"}, a)
+	require.NotNil(s.T(), a.UnmarshalText([]byte("12,50 EUR")))
+}
@@ -0,0 +1,257 @@
+package convert
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDateLayouts are candidate layouts of DateParser without Layouts. Day first and
+// month first layouts are both listed, the order is decided for the whole column by
+// ParseColumn.
+var DefaultDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"02.01.2006",
+	"02.01.06",
+	"02/01/2006",
+	"01/02/2006",
+	"2 Jan 2006",
+	"2 Jan 06",
+	"Jan 2, 2006",
+	"02.01.",
+	"2 Jan",
+	"Jan-02",
+}
+
+// maxSerial is the serial number of 9999-12-31
+const maxSerial = 2958465
+
+// DateParser parses dates written in one of the layouts
+type DateParser struct {
+	// Layouts are Go time layouts tried in the order, DefaultDateLayouts when empty
+	Layouts []string
+	// Reference is a date close to the parsed dates, e.g. end of a statement period.
+	// Dates without year get the year which puts them closest to the reference.
+	Reference time.Time
+	// Serial accepts spreadsheet serial numbers, counted in the 1904 date system when
+	// Date1904 is set
+	Serial   bool
+	Date1904 bool
+}
+
+// DateError describes a cell which isn't a date, Index is the index of the cell in the
+// column. Reason is set when the cell is a date, but its day and month order conflicts
+// with another cell or can't be decided.
+type DateError struct {
+	Index  int
+	Value  string
+	Reason string
+}
+
+// Error implements error
+func (e *DateError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("can't parse date %q in cell %d: %s", e.Value, e.Index, e.Reason)
+	}
+	return fmt.Sprintf("can't parse date %q in cell %d", e.Value, e.Index)
+}
+
+func (p DateParser) layouts() []string {
+	if len(p.Layouts) == 0 {
+		return DefaultDateLayouts
+	}
+	return p.Layouts
+}
+
+// Parse parses the cell with the first matching layout
+func (p DateParser) Parse(cell string) (time.Time, error) {
+	cell = strings.TrimSpace(cell)
+	for _, layout := range p.layouts() {
+		if t, err := p.parseLayout(cell, layout); err == nil {
+			return t, nil
+		}
+	}
+	if t, ok := p.parseSerial(cell); ok {
+		return t, nil
+	}
+	return time.Time{}, errors.Errorf("can't parse date %q", cell)
+}
+
+// ParseColumn parses all cells of a column with one day and month order. The order is
+// decided by the cells which only layouts of one order parse (like 13.02.2018), it's an
+// error when such cells disagree or when there is none and some cell can be read both
+// ways (like 01/02/2018). Layouts with month name or starting with year don't depend on
+// the order. Empty cells are returned as zero time.
+func (p DateParser) ParseColumn(cells []string) ([]time.Time, error) {
+	order, decidedBy, ambiguous := orderNone, "", -1
+	for i, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		orders := map[dateOrder]bool{}
+		for _, layout := range p.layouts() {
+			if _, err := p.parseLayout(cell, layout); err == nil {
+				orders[layoutOrder(layout)] = true
+			}
+		}
+		if _, ok := p.parseSerial(cell); cell != "" && len(orders) == 0 && !ok {
+			return nil, &DateError{Index: i, Value: cell}
+		}
+		switch {
+		case cell == "" || orders[orderNone]:
+		case orders[orderDayFirst] && orders[orderMonthFirst]:
+			if ambiguous < 0 {
+				ambiguous = i
+			}
+		case orders[orderDayFirst] || orders[orderMonthFirst]:
+			cellOrder := orderDayFirst
+			if orders[orderMonthFirst] {
+				cellOrder = orderMonthFirst
+			}
+			if order != orderNone && cellOrder != order {
+				return nil, &DateError{Index: i, Value: cell, Reason: fmt.Sprintf(
+					"day and month order differs from %q", decidedBy)}
+			}
+			if order == orderNone {
+				order, decidedBy = cellOrder, cell
+			}
+		}
+	}
+	if order == orderNone && ambiguous >= 0 {
+		return nil, &DateError{Index: ambiguous, Value: strings.TrimSpace(cells[ambiguous]),
+			Reason: "day and month order is ambiguous"}
+	}
+	decided := p
+	decided.Layouts = nil
+	for _, layout := range p.layouts() {
+		if o := layoutOrder(layout); o == orderNone || o == order {
+			decided.Layouts = append(decided.Layouts, layout)
+		}
+	}
+	result := make([]time.Time, len(cells))
+	for i, cell := range cells {
+		if strings.TrimSpace(cell) == "" {
+			continue
+		}
+		t, err := decided.Parse(cell)
+		if err != nil {
+			return nil, &DateError{Index: i, Value: strings.TrimSpace(cell)}
+		}
+		result[i] = t
+	}
+	return result, nil
+}
+
+// dateOrder is the order of numeric day and month in a layout
+type dateOrder int
+
+const (
+	orderNone dateOrder = iota
+	orderDayFirst
+	orderMonthFirst
+)
+
+// layoutTokens are Go time layout elements without month name, longer ones go first
+var layoutTokens = []string{
+	"Monday", "2006", "Mon", "MST", "_2", "01", "02", "03", "04", "05", "06", "15",
+	"1", "2", "3", "4", "5",
+}
+
+// layoutOrder returns order of numeric day and month in the layout, layouts with month
+// name or year before month are orderNone
+func layoutOrder(layout string) dateOrder {
+	if strings.Contains(layout, "Jan") {
+		return orderNone
+	}
+	for rest := layout; rest != ""; {
+		token := ""
+		for _, t := range layoutTokens {
+			if strings.HasPrefix(rest, t) {
+				token = t
+				break
+			}
+		}
+		switch token {
+		case "":
+			rest = rest[1:]
+			continue
+		case "2006", "06":
+			return orderNone
+		case "01", "1":
+			return orderMonthFirst
+		case "02", "_2", "2":
+			return orderDayFirst
+		}
+		rest = rest[len(token):]
+	}
+	return orderNone
+}
+
+func (p DateParser) parseLayout(cell, layout string) (time.Time, error) {
+	t, err := time.Parse(layout, cell)
+	if err != nil || hasYear(layout) {
+		return t, err
+	}
+	return p.inferYear(t)
+}
+
+// hasYear checks whether the layout contains year, 06 is a part of 2006 too
+func hasYear(layout string) bool {
+	return strings.Contains(layout, "06")
+}
+
+// inferYear sets the year which puts the date closest to the reference, a day which
+// doesn't exist in a year (29 February) is skipped
+func (p DateParser) inferYear(t time.Time) (time.Time, error) {
+	if p.Reference.IsZero() {
+		return t, errors.New("can't infer year without reference date")
+	}
+	var best time.Time
+	var bestDistance time.Duration = math.MaxInt64
+	for year := p.Reference.Year() - 1; year <= p.Reference.Year()+1; year++ {
+		candidate := time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(),
+			t.Nanosecond(), t.Location())
+		if candidate.Day() != t.Day() {
+			continue
+		}
+		distance := candidate.Sub(p.Reference)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, nil
+}
+
+func (p DateParser) parseSerial(cell string) (time.Time, bool) {
+	if !p.Serial || cell == "" || strings.Trim(cell, "0123456789.") != "" {
+		return time.Time{}, false
+	}
+	serial, err := strconv.ParseFloat(cell, 64)
+	if err != nil || serial < 1 || serial > maxSerial {
+		return time.Time{}, false
+	}
+	return SerialToTime(serial, p.Date1904), true
+}
+
+// SerialToTime converts spreadsheet serial date number to time. Days are counted from
+// 1900-01-00 in the 1900 date system (including its non existing 1900-02-29) or from
+// 1904-01-01 in the 1904 date system, fraction is the time of the day.
+func SerialToTime(serial float64, date1904 bool) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	switch {
+	case date1904:
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	case serial < 61:
+		// before the non existing 1900-02-29
+		epoch = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 24 * 60 * 60)
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+}
@@ -0,0 +1,112 @@
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type dateSuite struct{ suite.Suite }
+
+func TestDate(t *testing.T) { suite.Run(t, new(dateSuite)) }
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *dateSuite) TestParse() {
+	p := DateParser{Reference: date(2018, 1, 15), Serial: true}
+	testcases := map[string]time.Time{
+		"2018-02-01":          date(2018, 2, 1),
+		"02.01.2006":          date(2006, 1, 2),
+		"2 Jan 06":            date(2006, 1, 2),
+		" 12 Mar 2018 ":       date(2018, 3, 12),
+		"Jan-02":              date(2018, 1, 2),
+		"Dec-28":              date(2017, 12, 28),
+		"24.12.":              date(2017, 12, 24),
+		"43132":               date(2018, 2, 1),
+		"43132.5":             time.Date(2018, 2, 1, 12, 0, 0, 0, time.UTC),
+		"2018-02-01 10:30:00": time.Date(2018, 2, 1, 10, 30, 0, 0, time.UTC),
+	}
+	for input, result := range testcases {
+		t, err := p.Parse(input)
+		require.Nil(s.T(), err, input)
+		require.Equal(s.T(), result, t, input)
+	}
+	for _, input := range []string{"", "32.01.2018", "tomorrow", "0", "1e5", "-5"} {
+		_, err := p.Parse(input)
+		require.NotNil(s.T(), err, input)
+	}
+}
+
+func (s *dateSuite) TestYearInference() {
+	p := DateParser{Layouts: []string{"02.01."}}
+	_, err := p.Parse("01.02.")
+	require.NotNil(s.T(), err)
+
+	p.Reference = date(2021, 1, 10)
+	t, err := p.Parse("29.02.")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), date(2020, 2, 29), t)
+
+	_, err = DateParser{Layouts: []string{"02.01."}, Serial: false}.Parse("43132")
+	require.NotNil(s.T(), err)
+}
+
+func (s *dateSuite) TestParseColumn() {
+	p := DateParser{Layouts: []string{"01/02/2006", "02/01/2006"}}
+	// 13/02/2018 can only be day first, so 01/02/2018 is the 1st of February
+	dates, err := p.ParseColumn([]string{"01/02/2018", "", "13/02/2018"})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []time.Time{date(2018, 2, 1), {}, date(2018, 2, 13)}, dates)
+
+	_, err = p.ParseColumn([]string{"01/02/2018", "13/02/2018", "02/14/2018"})
+	require.Equal(s.T(), &DateError{Index: 2, Value: "02/14/2018",
+		Reason: `day and month order differs from "13/02/2018"`}, err)
+
+	_, err = p.ParseColumn([]string{"01/02/2018", "03/04/2018"})
+	require.Equal(s.T(), &DateError{Index: 0, Value: "01/02/2018",
+		Reason: "day and month order is ambiguous"}, err)
+	require.Equal(s.T(),
+		`can't parse date "01/02/2018" in cell 0: day and month order is ambiguous`, err.Error())
+
+	// a single order is never ambiguous
+	dates, err = DateParser{Layouts: []string{"01/02/2006"}}.ParseColumn([]string{"01/02/2018"})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []time.Time{date(2018, 1, 2)}, dates)
+
+	_, err = p.ParseColumn([]string{"01/02/2018", "13/13/2018"})
+	require.Equal(s.T(), &DateError{Index: 1, Value: "13/13/2018"}, err)
+	require.Equal(s.T(), `can't parse date "13/13/2018" in cell 1`, err.Error())
+
+	// mixed layouts in one column, month names and years first don't decide the order
+	dates, err = DateParser{Reference: date(2018, 3, 1), Serial: true}.ParseColumn(
+		[]string{"02.01.2018", "2 Feb 18", "Mar-03", "43132", "2018-02-05", "03/02/2018"})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []time.Time{
+		date(2018, 1, 2), date(2018, 2, 2), date(2018, 3, 3), date(2018, 2, 1),
+		date(2018, 2, 5), date(2018, 2, 3),
+	}, dates)
+}
+
+func (s *dateSuite) TestLayoutOrder() {
+	for layout, order := range map[string]dateOrder{
+		"02.01.2006":          orderDayFirst,
+		"_2/1/06":             orderDayFirst,
+		"01/02/2006":          orderMonthFirst,
+		"1/2 15:04":           orderMonthFirst,
+		"2006-01-02 15:04:05": orderNone,
+		"2 Jan 2006":          orderNone,
+		"Jan-02":              orderNone,
+	} {
+		require.Equal(s.T(), order, layoutOrder(layout), layout)
+	}
+}
+
+func (s *dateSuite) TestSerialToTime() {
+	require.Equal(s.T(), date(1900, 1, 1), SerialToTime(1, false))
+	require.Equal(s.T(), date(1900, 3, 1), SerialToTime(61, false))
+	require.Equal(s.T(), date(1904, 1, 2), SerialToTime(1, true))
+}
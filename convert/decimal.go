@@ -0,0 +1,178 @@
+// Package convert converts table cells to typed values
+package convert
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Decimal is an exact decimal number, its value is unscaled * 10^-scale. The zero
+// value is 0, decimals are immutable.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// NewDecimal returns unscaled * 10^-scale
+func NewDecimal(unscaled int64, scale int) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal parses number with optional sign and decimal point like -1234.56
+func ParseDecimal(s string) (Decimal, error) {
+	return parseNumber(s, Plain)
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale returns number of digits after the decimal point
+func (d Decimal) Scale() int { return d.scale }
+
+// Sign returns -1, 0 or 1 for negative, zero and positive decimal
+func (d Decimal) Sign() int { return d.int().Sign() }
+
+// rescale returns unscaled value of the decimal with greater or equal scale
+func (d Decimal) rescale(scale int) *big.Int {
+	exp := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale-d.scale)), nil)
+	return exp.Mul(exp, d.int())
+}
+
+// Cmp compares decimals and returns -1, 0 or 1 when d is less, equal or greater than e
+func (d Decimal) Cmp(e Decimal) int {
+	scale := maxInt(d.scale, e.scale)
+	return d.rescale(scale).Cmp(e.rescale(scale))
+}
+
+// Add returns d + e
+func (d Decimal) Add(e Decimal) Decimal {
+	scale := maxInt(d.scale, e.scale)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), e.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - e
+func (d Decimal) Sub(e Decimal) Decimal { return d.Add(e.Neg()) }
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Float64 returns the nearest float64 value
+func (d Decimal) Float64() float64 {
+	if d.scale <= 0 {
+		f, _ := new(big.Rat).SetInt(d.rescale(0)).Float64()
+		return f
+	}
+	exp := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale)), nil)
+	f, _ := new(big.Rat).SetFrac(d.int(), exp).Float64()
+	return f
+}
+
+// String formats the decimal with all its digits, e.g. -1234.50
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		if d.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", -d.scale)
+	}
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+	point := len(digits) - d.scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, text is parsed by ParseDecimal
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// parseNumber parses number with optional leading sign written in the locale
+func parseNumber(s string, l Locale) (Decimal, error) {
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	intPart, fraction := s, ""
+	if i := strings.IndexRune(s, l.Decimal); i >= 0 {
+		intPart, fraction = s[:i], s[i+len(string(l.Decimal)):]
+	}
+	digits, ok := ungroup(intPart, l.Group)
+	if !ok || !allDigits(fraction) || digits+fraction == "" {
+		return Decimal{}, errors.Errorf("can't parse number %q", s)
+	}
+	unscaled, _ := new(big.Int).SetString(digits+fraction, 10)
+	if neg {
+		unscaled.Neg(unscaled)
+	}
+	return Decimal{unscaled: unscaled, scale: len(fraction)}, nil
+}
+
+// ungroup removes group separators from the integer part. Groups following the first
+// one must have three digits and all separators must be the same.
+func ungroup(s, separators string) (string, bool) {
+	i := strings.IndexAny(s, separators)
+	if i < 0 {
+		return s, allDigits(s)
+	}
+	first := s[:i]
+	if first == "" || len(first) > 3 || !allDigits(first) {
+		return "", false
+	}
+	digits := first
+	for _, r := range separators {
+		if !strings.HasPrefix(s[i:], string(r)) {
+			continue
+		}
+		for _, group := range strings.Split(s[i+len(string(r)):], string(r)) {
+			if len(group) != 3 || !allDigits(group) {
+				return "", false
+			}
+			digits += group
+		}
+		return digits, true
+	}
+	return "", false
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
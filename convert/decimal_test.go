@@ -0,0 +1,66 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type decimalSuite struct{ suite.Suite }
+
+func TestDecimal(t *testing.T) { suite.Run(t, new(decimalSuite)) }
+
+func (s *decimalSuite) TestParseDecimal() {
+	for input, result := range map[string]string{
+		"1234.56":                   "1234.56",
+		"-0.05":                     "-0.05",
+		"+7":                        "7",
+		"007.50":                    "7.50",
+		".5":                        "0.5",
+		"5.":                        "5",
+		"12345678901234567890.0123": "12345678901234567890.0123",
+	} {
+		d, err := ParseDecimal(input)
+		require.Nil(s.T(), err, input)
+		require.Equal(s.T(), result, d.String(), input)
+	}
+	for _, input := range []string{"", "-", ".", "1,5", "1e5", "1.2.3", " 1"} {
+		_, err := ParseDecimal(input)
+		require.NotNil(s.T(), err, input)
+	}
+}
+
+func (s *decimalSuite) TestArithmetic() {
+	a, b := NewDecimal(1050, 2), NewDecimal(-3, 0)
+	require.Equal(s.T(), "7.50", a.Add(b).String())
+	require.Equal(s.T(), "13.50", a.Sub(b).String())
+	require.Equal(s.T(), "-10.50", a.Neg().String())
+	require.Equal(s.T(), 1, a.Cmp(b))
+	require.Equal(s.T(), -1, b.Cmp(a))
+	require.Equal(s.T(), 0, NewDecimal(75, 1).Cmp(a.Add(b)))
+	require.Equal(s.T(), 2, a.Scale())
+	require.Equal(s.T(), -1, b.Sign())
+
+	var zero Decimal
+	require.Equal(s.T(), "0", zero.String())
+	require.Equal(s.T(), 0, zero.Sign())
+	require.Equal(s.T(), "10.50", zero.Add(a).String())
+	require.Equal(s.T(), "1200", NewDecimal(12, -2).String())
+}
+
+func (s *decimalSuite) TestFloat64() {
+	require.Equal(s.T(), 10.5, NewDecimal(1050, 2).Float64())
+	require.Equal(s.T(), -0.1, NewDecimal(-1, 1).Float64())
+	require.Equal(s.T(), 1200.0, NewDecimal(12, -2).Float64())
+	require.Equal(s.T(), 0.0, Decimal{}.Float64())
+}
+
+func (s *decimalSuite) TestText() {
+	var d Decimal
+	require.Nil(s.T(), d.UnmarshalText([]byte("-12.30")))
+	text, err := d.MarshalText()
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), "-12.30", string(text))
+	require.NotNil(s.T(), d.UnmarshalText([]byte("12,30")))
+}
@@ -0,0 +1,197 @@
+package table
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSniffSize is the number of bytes inspected by SniffCSV by default
+const DefaultSniffSize = 16 * 1024
+
+var (
+	utf8BOM       = []byte("\xef\xbb\xbf")
+	sniffedCommas = []rune{',', ';', '\t', '|'}
+)
+
+// Dialect of CSV input as detected by SniffCSV
+type Dialect struct {
+	// Comma is the field delimiter: comma, semicolon, tab or pipe
+	Comma rune
+	// Comment is the character starting comment lines, 0 when there are none
+	Comment rune
+	// Quoted is set when some fields are enclosed in double quotes
+	Quoted bool
+	// LazyQuotes is set when quotes appear inside of unquoted fields, so the input can
+	// be read only with csv.Reader.LazyQuotes
+	LazyQuotes bool
+	// HasHeader is set when the first row looks like a header of the rows below
+	HasHeader bool
+	// BOM is set when the input starts with UTF-8 byte order mark (it is skipped)
+	BOM bool
+}
+
+// SniffCSV inspects the beginning of the input (DefaultSniffSize bytes unless size is
+// given) and returns CSV configured to read it together with the detected dialect.
+// Records of the returned reader can have variable number of fields.
+func SniffCSV(r io.Reader, size ...int) (CSV, Dialect, error) {
+	n := DefaultSniffSize
+	if len(size) > 0 && size[0] > 0 {
+		n = size[0]
+	}
+	buffered := bufio.NewReaderSize(r, n)
+	sample, err := buffered.Peek(n)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return CSV{}, Dialect{}, errors.Wrap(err, "can't read csv sample")
+	}
+	truncated := len(sample) == n
+	var d Dialect
+	if bytes.HasPrefix(sample, utf8BOM) {
+		d.BOM = true
+		sample = sample[len(utf8BOM):]
+		if _, err := buffered.Discard(len(utf8BOM)); err != nil {
+			return CSV{}, d, errors.Wrap(err, "can't skip byte order mark")
+		}
+	}
+	if truncated {
+		// the last line is probably incomplete
+		if i := bytes.LastIndexByte(sample, '\n'); i >= 0 {
+			sample = sample[:i+1]
+		}
+	}
+	d = sniffDialect(string(sample), d)
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = d.Comma
+	reader.Comment = d.Comment
+	reader.LazyQuotes = d.LazyQuotes
+	reader.FieldsPerRecord = -1
+	return CSV{Reader: reader}, d, nil
+}
+
+func sniffDialect(sample string, d Dialect) Dialect {
+	d.Comma = ','
+	d.Comment = sniffComment(sample)
+	bestScore := 0.0
+	for _, comma := range sniffedCommas {
+		rows, err := readSample(sample, comma, d.Comment, true)
+		if score := consistency(rows); err == nil && score > bestScore {
+			d.Comma, bestScore = comma, score
+		}
+	}
+	rows, _ := readSample(sample, d.Comma, d.Comment, true)
+	_, err := readSample(sample, d.Comma, d.Comment, false)
+	d.LazyQuotes = err != nil
+	d.Quoted = quotedFields(sample, d.Comma)
+	d.HasHeader = hasHeader(rows)
+	return d
+}
+
+func readSample(sample string, comma, comment rune, lazyQuotes bool) ([][]string, error) {
+	reader := csv.NewReader(strings.NewReader(sample))
+	reader.Comma = comma
+	reader.Comment = comment
+	reader.LazyQuotes = lazyQuotes
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// consistency returns share of rows having the most common number of fields, rows with
+// a single field don't count
+func consistency(rows [][]string) float64 {
+	counts := map[int]int{}
+	for _, row := range rows {
+		counts[len(row)]++
+	}
+	best := 0
+	for n, count := range counts {
+		if n > 1 && count > best {
+			best = count
+		}
+	}
+	if len(rows) == 0 {
+		return 0
+	}
+	return float64(best) / float64(len(rows))
+}
+
+// sniffComment detects lines starting with #
+func sniffComment(sample string) rune {
+	for _, line := range strings.Split(sample, "\n") {
+		if strings.HasPrefix(line, "#") {
+			return '#'
+		}
+	}
+	return 0
+}
+
+// quotedFields checks whether a field starts with a quote
+func quotedFields(sample string, comma rune) bool {
+	for _, line := range strings.Split(sample, "\n") {
+		for _, f := range strings.Split(line, string(comma)) {
+			if strings.HasPrefix(strings.TrimSpace(f), `"`) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasHeader compares the first row with the rest of rows of the same length: a column
+// votes for the header when its values are numeric and the first one is not, or when the
+// values have constant length different from the first one
+func hasHeader(rows [][]string) bool {
+	if len(rows) < 2 {
+		return false
+	}
+	header := rows[0]
+	var data [][]string
+	for _, row := range rows[1:] {
+		if len(row) == len(header) {
+			data = append(data, row)
+		}
+	}
+	if len(data) == 0 {
+		return false
+	}
+	votes := 0
+	for i, name := range header {
+		numeric, length := true, len(data[0][i])
+		for _, row := range data {
+			numeric = numeric && looksNumeric(row[i])
+			if len(row[i]) != length {
+				length = -1
+			}
+		}
+		switch {
+		case numeric && looksNumeric(name):
+			votes--
+		case numeric:
+			votes++
+		case length >= 0 && length != len(name):
+			votes++
+		case length >= 0:
+			votes--
+		}
+	}
+	return votes > 0
+}
+
+// looksNumeric matches amounts, dates and times
+func looksNumeric(s string) bool {
+	s = strings.TrimSpace(s)
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case !strings.ContainsRune("-+.,/:'() %", r):
+			return false
+		}
+	}
+	return digits > 0
+}
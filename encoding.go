@@ -0,0 +1,165 @@
+package table
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Encoding of the input as detected by DetectEncoding
+type Encoding string
+
+// Encodings recognised by DetectEncoding
+const (
+	UTF8        Encoding = "UTF-8"
+	UTF16LE     Encoding = "UTF-16LE"
+	UTF16BE     Encoding = "UTF-16BE"
+	Windows1252 Encoding = "windows-1252"
+	ISO88591    Encoding = "ISO-8859-1"
+)
+
+// windows1252 maps bytes 0x80-0x9f to runes, the rest of the code page is equal to
+// ISO-8859-1. Bytes undefined in the code page are mapped to C1 controls.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡',
+	'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—',
+	'˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+var (
+	utf16LEBOM = []byte{0xff, 0xfe}
+	utf16BEBOM = []byte{0xfe, 0xff}
+)
+
+// DetectEncoding guesses encoding of the input. Byte order marks are recognised first,
+// UTF-16 without BOM is recognised by zero bytes of ASCII characters. Input which is not
+// valid UTF-8 is expected to be windows-1252 if it contains bytes 0x80-0x9f (which are
+// control characters in ISO-8859-1), ISO-8859-1 otherwise.
+func DetectEncoding(b []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(b, utf8BOM):
+		return UTF8
+	case bytes.HasPrefix(b, utf16LEBOM):
+		return UTF16LE
+	case bytes.HasPrefix(b, utf16BEBOM):
+		return UTF16BE
+	}
+	if e, ok := detectUTF16(b); ok {
+		return e
+	}
+	if utf8.Valid(b) {
+		return UTF8
+	}
+	for _, c := range b {
+		if c >= 0x80 && c <= 0x9f {
+			return Windows1252
+		}
+	}
+	return ISO88591
+}
+
+// detectUTF16 looks for zero bytes which are high bytes of ASCII characters
+func detectUTF16(b []byte) (Encoding, bool) {
+	if len(b) < 4 {
+		return "", false
+	}
+	var even, odd int
+	for i, c := range b {
+		if c != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			even++
+		} else {
+			odd++
+		}
+	}
+	half := len(b) / 2
+	switch {
+	case odd > half/2 && even == 0:
+		return UTF16LE, true
+	case even > half/2 && odd == 0:
+		return UTF16BE, true
+	}
+	return "", false
+}
+
+// Decode converts input to UTF-8 string, the encoding is detected by DetectEncoding and
+// byte order mark is removed
+func Decode(b []byte) (string, Encoding, error) {
+	e := DetectEncoding(b)
+	s, err := DecodeAs(b, e)
+	return s, e, err
+}
+
+// DecodeAs converts input in the given encoding to UTF-8 string, byte order mark is
+// removed
+func DecodeAs(b []byte, e Encoding) (string, error) {
+	switch e {
+	case UTF8:
+		return string(bytes.TrimPrefix(b, utf8BOM)), nil
+	case UTF16LE:
+		return decodeUTF16(bytes.TrimPrefix(b, utf16LEBOM), binary.LittleEndian)
+	case UTF16BE:
+		return decodeUTF16(bytes.TrimPrefix(b, utf16BEBOM), binary.BigEndian)
+	case Windows1252, ISO88591:
+		var sb strings.Builder
+		for _, c := range b {
+			if e == Windows1252 && c >= 0x80 && c <= 0x9f {
+				sb.WriteRune(windows1252[c-0x80])
+				continue
+			}
+			sb.WriteRune(rune(c))
+		}
+		return sb.String(), nil
+	}
+	return "", errors.Errorf("unsupported encoding %q", e)
+}
+
+func decodeUTF16(b []byte, order binary.ByteOrder) (string, error) {
+	if len(b)%2 != 0 {
+		return "", errors.New("can't decode UTF-16: odd number of bytes")
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = order.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// DecodeReader reads whole input and returns reader of its UTF-8 version, it can be
+// used to create CSV or passed to goquery
+func DecodeReader(r io.Reader) (io.Reader, Encoding, error) {
+	s, e, err := readDecoded(r)
+	return strings.NewReader(s), e, err
+}
+
+// DecodeLines reads whole input and splits its UTF-8 version into lines, it can be used
+// as T or as an input of ParseAligned and ParseBoxes. Both \n and \r\n line ends are
+// recognised.
+func DecodeLines(r io.Reader) (T, Encoding, error) {
+	s, e, err := readDecoded(r)
+	if err != nil {
+		return nil, e, err
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return T(lines), e, nil
+}
+
+func readDecoded(r io.Reader) (string, Encoding, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", "", errors.Wrap(err, "can't read input")
+	}
+	return Decode(b)
+}
@@ -0,0 +1,71 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type encodingSuite struct{ suite.Suite }
+
+func TestEncoding(t *testing.T) { suite.Run(t, new(encodingSuite)) }
+
+func (s *encodingSuite) TestDecode() {
+	testcases := []struct {
+		input    []byte
+		encoding Encoding
+	}{
+		{[]byte("Café 5 €"), UTF8},
+		{[]byte("\xef\xbb\xbfCafé 5 €"), UTF8},
+		{[]byte("Caf\xe9 5 \x80"), Windows1252},
+		{[]byte("\xff\xfeC\x00a\x00f\x00\xe9\x00 \x005\x00 \x00\xac\x20"), UTF16LE},
+		{[]byte("\xfe\xff\x00C\x00a\x00f\x00\xe9\x00 \x005\x00 \x20\xac"), UTF16BE},
+		{[]byte("C\x00a\x00f\x00\xe9\x00 \x005\x00 \x00\xac\x20"), UTF16LE},
+	}
+	for _, t := range testcases {
+		decoded, encoding, err := Decode(t.input)
+		require.Nil(s.T(), err)
+		require.Equal(s.T(), t.encoding, encoding)
+		require.Equal(s.T(), "Café 5 €", decoded)
+	}
+}
+
+func (s *encodingSuite) TestDecodeISO88591() {
+	decoded, encoding, err := Decode([]byte("Gr\xfc\xdfe"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), ISO88591, encoding)
+	require.Equal(s.T(), "Grüße", decoded)
+}
+
+func (s *encodingSuite) TestDecodeLinesForAlignedTable() {
+	input := []byte("Name       Amount\r\nM\xfcller       10,00 \x80\r\nZo\xeb          7,50 \x80")
+	lines, encoding, err := DecodeLines(bytes.NewReader(input))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), Windows1252, encoding)
+	result, err := ParseAligned(lines, 2)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Müller", "10,00 €"}, trimSpace(result.Lines()[1]))
+}
+
+func (s *encodingSuite) TestDecodeReaderForCSV() {
+	input := "\xff\xfe" + string(utf16LE("Date,Amount\n01.02.2018,4.50 €\n"))
+	reader, encoding, err := DecodeReader(strings.NewReader(input))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), UTF16LE, encoding)
+	csv, _, err := SniffCSV(reader)
+	require.Nil(s.T(), err)
+	row, err := csv.Reader.Read()
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Date", "Amount"}, row)
+}
+
+func utf16LE(s string) []byte {
+	var b []byte
+	for _, r := range s {
+		b = append(b, byte(r), byte(r>>8))
+	}
+	return b
+}
@@ -4,15 +4,17 @@ import (
 	"strings"
 )
 
+// field found in a line, from is expressed in display cells
 type field struct {
 	value string
 	from  int
 }
 
-func (f *field) To() int { return f.from + len(f.value) }
+func (f *field) To() int { return f.from + displayWidth(f.value) }
 
 func extractFields(s string) []field {
 	var linePosition int
+	s = expandTabs(s)
 	fieldValues := Fields(s)
 	fields := make([]field, 0, len(fieldValues))
 	for _, fieldValue := range fieldValues {
@@ -21,9 +23,10 @@ func extractFields(s string) []field {
 			continue
 		}
 		index := strings.Index(s, fieldValue)
-		fields = append(fields, field{value: fieldValue, from: index + linePosition})
+		from := linePosition + displayWidth(s[:index])
+		fields = append(fields, field{value: fieldValue, from: from})
 		s = s[index+len(fieldValue):]
-		linePosition += index + len(fieldValue)
+		linePosition = from + displayWidth(fieldValue)
 	}
 	return fields
 }
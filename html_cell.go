@@ -0,0 +1,93 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Cell of a HTML table with details lost in the plain text of Parsed
+type Cell struct {
+	// Text of the cell with whitespace collapsed to single spaces, <br> and block
+	// elements break lines with "\n"
+	Text string
+	// Attrs contains attributes of the td or th element (data-*, title, class...)
+	Attrs map[string]string
+	// Links found inside the cell in the document order
+	Links []Link
+}
+
+// Link found inside a table cell
+type Link struct {
+	Href, Text string
+}
+
+// Attr returns attribute of the cell and whether it is present
+func (c Cell) Attr(name string) (string, bool) {
+	val, ok := c.Attrs[name]
+	return val, ok
+}
+
+// elements starting a new line of text
+var blockElements = map[string]bool{
+	"address": true, "blockquote": true, "div": true, "dl": true, "dt": true, "dd": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true,
+	"li": true, "ol": true, "p": true, "pre": true, "ul": true,
+}
+
+func newCell(s *goquery.Selection) Cell {
+	c := Cell{Attrs: map[string]string{}}
+	for _, n := range s.Nodes {
+		for _, a := range n.Attr {
+			c.Attrs[a.Key] = a.Val
+		}
+	}
+	var b strings.Builder
+	writeCellText(&b, s)
+	c.Text = normalizeLines(b.String())
+	s.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		// links of nested tables belong to them
+		if !a.Closest("td, th").IsSelection(s) {
+			return
+		}
+		href, _ := a.Attr("href")
+		c.Links = append(c.Links, Link{Href: href, Text: normalizeLines(a.Text())})
+	})
+	return c
+}
+
+func writeCellText(b *strings.Builder, s *goquery.Selection) {
+	s.Contents().Each(func(_ int, child *goquery.Selection) {
+		switch name := goquery.NodeName(child); {
+		case name == "#text":
+			// new lines of the source are just whitespace
+			b.WriteString(strings.Replace(child.Text(), "\n", " ", -1))
+		case name == "br":
+			b.WriteString("\n")
+		case name == "table":
+			// nested tables are parsed on their own
+		case blockElements[name]:
+			b.WriteString("\n")
+			writeCellText(b, child)
+			b.WriteString("\n")
+		default:
+			writeCellText(b, child)
+		}
+	})
+}
+
+// normalizeLines collapses whitespace inside of each line, empty lines at the beginning
+// and at the end are removed
+func normalizeLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
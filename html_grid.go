@@ -0,0 +1,178 @@
+package table
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SpanFill tells how slots covered by colspan or rowspan are filled
+type SpanFill int
+
+// Ways of filling slots covered by a spanning cell
+const (
+	// FillEmpty leaves covered slots empty, only the first slot has the value
+	FillEmpty SpanFill = iota
+	// FillRepeat repeats value of the spanning cell in each covered slot
+	FillRepeat
+)
+
+// limits of spans defined by the HTML table processing model
+const (
+	maxColspan = 1000
+	maxRowspan = 65534
+)
+
+// htmlGrid places cells of a table into slots following the HTML table processing model.
+// Like browsers do, rowspan is clipped to the row group (thead, tbody or tfoot) the cell
+// belongs to, rowspan="0" spans to the end of the group.
+type htmlGrid struct {
+	fill     SpanFill
+	slots    [][]gridSlot
+	sections []Section
+	width    int
+}
+
+type gridSlot struct {
+	value  string
+	cell   Cell
+	filled bool
+}
+
+func newHTMLGrid(table *goquery.Selection, fill SpanFill) *htmlGrid {
+	g := &htmlGrid{fill: fill}
+	var pending []*goquery.Selection
+	table.Children().Each(func(_ int, child *goquery.Selection) {
+		switch goquery.NodeName(child) {
+		case "tr":
+			// rows outside of a row group form an implicit body
+			pending = append(pending, child)
+		case "thead", "tbody", "tfoot":
+			g.addGroup(pending, Body)
+			pending = nil
+			var rows []*goquery.Selection
+			child.ChildrenFiltered("tr").Each(func(_ int, tr *goquery.Selection) {
+				rows = append(rows, tr)
+			})
+			g.addGroup(rows, sectionOf(child))
+		}
+	})
+	g.addGroup(pending, Body)
+	return g
+}
+
+func sectionOf(group *goquery.Selection) Section {
+	switch goquery.NodeName(group) {
+	case "thead":
+		return Head
+	case "tfoot":
+		return Foot
+	}
+	return Body
+}
+
+func (g *htmlGrid) addGroup(rows []*goquery.Selection, section Section) {
+	start := len(g.slots)
+	for range rows {
+		g.slots = append(g.slots, nil)
+		g.sections = append(g.sections, section)
+	}
+	for i, tr := range rows {
+		y := start + i
+		x := 0
+		tr.ChildrenFiltered("td, th").Each(func(_ int, cell *goquery.Selection) {
+			for x < len(g.slots[y]) && g.slots[y][x].filled {
+				x++
+			}
+			colspan := parseSpan(cell, "colspan", maxColspan)
+			if colspan == 0 {
+				colspan = 1
+			}
+			rowspan := parseSpan(cell, "rowspan", maxRowspan)
+			if rowspan == 0 || rowspan > len(rows)-i {
+				rowspan = len(rows) - i
+			}
+			g.place(x, y, colspan, rowspan, gridSlot{value: cellText(cell), cell: newCell(cell)})
+			x += colspan
+		})
+	}
+}
+
+// cellText returns text of the cell without content of tables nested inside
+func cellText(cell *goquery.Selection) string {
+	if cell.Find("table").Length() > 0 {
+		cell = cell.Clone()
+		cell.Find("table").Remove()
+	}
+	return strings.TrimSpace(cell.Text())
+}
+
+// place the cell into slots, slots which are already covered by another cell are kept
+func (g *htmlGrid) place(x, y, colspan, rowspan int, slot gridSlot) {
+	if x+colspan > g.width {
+		g.width = x + colspan
+	}
+	for j := y; j < y+rowspan; j++ {
+		for len(g.slots[j]) < x+colspan {
+			g.slots[j] = append(g.slots[j], gridSlot{})
+		}
+		for i := x; i < x+colspan; i++ {
+			if g.slots[j][i].filled {
+				continue
+			}
+			if (i == x && j == y) || g.fill == FillRepeat {
+				g.slots[j][i] = slot
+			}
+			g.slots[j][i].filled = true
+		}
+	}
+}
+
+// rows returns content of the grid, each row has the width of the table
+func (g *htmlGrid) rows() [][]string {
+	result := make([][]string, len(g.slots))
+	for y, slots := range g.slots {
+		result[y] = make([]string, g.width)
+		for x, slot := range slots {
+			result[y][x] = slot.value
+		}
+	}
+	return result
+}
+
+// cells returns rich content of the grid, each row has the width of the table
+func (g *htmlGrid) cells() [][]Cell {
+	result := make([][]Cell, len(g.slots))
+	for y, slots := range g.slots {
+		result[y] = make([]Cell, g.width)
+		for x, slot := range slots {
+			result[y][x] = slot.cell
+		}
+	}
+	return result
+}
+
+// parseSpan reads span attribute according to the rules for parsing non-negative
+// integers: leading whitespace is skipped and digits are read up to the first other
+// character. Missing or invalid value is 1, values above max are clipped.
+func parseSpan(cell *goquery.Selection, name string, max int) int {
+	val, ok := cell.Attr(name)
+	if !ok {
+		return 1
+	}
+	val = strings.TrimLeft(val, " \t\n\f\r")
+	val = strings.TrimPrefix(val, "+")
+	end := 0
+	for end < len(val) && val[end] >= '0' && val[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 1
+	}
+	n, err := strconv.Atoi(val[:end])
+	if err != nil || n > max {
+		return max
+	}
+	return n
+}
@@ -1,5 +1,11 @@
 package table
 
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // Parsed represents parsed aligned table
 type Parsed []parsedLine
 
@@ -62,3 +68,138 @@ func (p Parsed) SkipOneLine() Parsed {
 	}
 	return p[1:]
 }
+
+// JoinContinuations merges continuation rows into the preceding row. It is meant for
+// tables whose long cells wrap onto following lines which have text only in some
+// columns. Non empty cells of a continuation row are appended (separated by a space) to
+// the cells of the row above, the original lines of the merged row are joined with a new
+// line, so predicates of FindLine, SkipTo or TakeTo see all of them. Rows with empty
+// cells only are never considered to be continuations.
+func (p Parsed) JoinContinuations(isContinuation func([]string) bool) Parsed {
+	result := Parsed{}
+	for _, line := range p {
+		if len(result) == 0 || stringsOnlyWhitespace(line.parsed) || !isContinuation(line.parsed) {
+			result = append(result, parsedLine{
+				original: line.original,
+				parsed:   append([]string{}, line.parsed...),
+			})
+			continue
+		}
+		last := &result[len(result)-1]
+		last.original += "\n" + line.original
+		for i, cell := range line.parsed {
+			if isWhiteSpace(cell) {
+				continue
+			}
+			if i >= len(last.parsed) {
+				last.parsed = append(last.parsed, make([]string, i-len(last.parsed)+1)...)
+			}
+			last.parsed[i] = join(strings.TrimSpace(last.parsed[i]), cell)
+		}
+	}
+	return result
+}
+
+// KeyColumnEmpty returns a continuation predicate for JoinContinuations which matches
+// rows having nothing in the given column
+func KeyColumnEmpty(column int) func([]string) bool {
+	return func(row []string) bool {
+		return column >= len(row) || isWhiteSpace(row[column])
+	}
+}
+
+// Table is a parsed table whose columns are named by its header
+type Table struct {
+	Parsed
+	columns []string
+}
+
+// Columns returns names of the table columns
+func (t Table) Columns() []string { return t.columns }
+
+// WithHeader returns table whose columns are named by the first line and whose rows
+// are the following lines. Header names are trimmed, an error is returned when there is
+// no header, a column has no name or a name is repeated.
+func (p Parsed) WithHeader() (Table, error) {
+	header, ok := p.Head()
+	if !ok {
+		return Table{Parsed: Parsed{}}, errors.New("can't find header, table is empty")
+	}
+	columns := trimSpace(append([]string(nil), header...))
+	seen := map[string]int{}
+	for i, c := range columns {
+		if c == "" {
+			return Table{Parsed: Parsed{}}, errors.Errorf(
+				"column %d has no name in header %q", i, columns)
+		}
+		if j, ok := seen[normalizeColumnName(c)]; ok {
+			return Table{Parsed: Parsed{}}, errors.Errorf(
+				"column %q is repeated at %d and %d in header", c, j, i)
+		}
+		seen[normalizeColumnName(c)] = i
+	}
+	return Table{Parsed: p.SkipOneLine(), columns: columns}, nil
+}
+
+// ColumnIndex returns index of the column with given name. Names are matched case
+// insensitively with whitespace collapsed, the first matching column is returned.
+func (t Table) ColumnIndex(name string) (int, error) {
+	return columnIndex(t.columns, name)
+}
+
+func columnIndex(columns []string, name string) (int, error) {
+	for i, c := range columns {
+		if normalizeColumnName(c) == normalizeColumnName(name) {
+			return i, nil
+		}
+	}
+	return -1, errors.Errorf("can't find column %q in %q", name, columns)
+}
+
+// Column returns cells of the column with given name, rows which are too short have an
+// empty cell
+func (t Table) Column(name string) ([]string, error) {
+	index, err := t.ColumnIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	cells := make([]string, len(t.Parsed))
+	for i, line := range t.Parsed {
+		if index < len(line.parsed) {
+			cells[i] = line.parsed[index]
+		}
+	}
+	return cells, nil
+}
+
+// Row returns i-th row of the table, it's an error when there is no such row
+func (t Table) Row(i int) (Row, error) {
+	if i < 0 || i >= len(t.Parsed) {
+		return Row{columns: t.columns}, errors.Errorf(
+			"can't find row %d, table has %d rows", i, len(t.Parsed))
+	}
+	return t.row(i), nil
+}
+
+func (t Table) row(i int) Row {
+	return Row{columns: t.columns, cells: t.Parsed[i].parsed}
+}
+
+// Row is a row of a table whose cells are addressable by column names
+type Row struct {
+	columns []string
+	cells   []string
+}
+
+// Cells returns cells of the row
+func (r Row) Cells() []string { return r.cells }
+
+// Get returns cell of the column with given name, the cell is empty when the row is
+// too short
+func (r Row) Get(name string) (string, error) {
+	index, err := columnIndex(r.columns, name)
+	if err != nil || index >= len(r.cells) {
+		return "", err
+	}
+	return r.cells[index], nil
+}
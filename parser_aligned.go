@@ -1,7 +1,10 @@
 package table
 
 import (
-	"unicode/utf8"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // ParseAligned table. Tries to parse table which looks like this:
@@ -13,81 +16,172 @@ import (
 // performs well and in which it does not.
 // Number of columns in the table needs to be provided.
 // If none of the rows has the expected number of column, error is returned.
-// WARNING: This function does work only with ASCII strings
-// (so result might be wrong for UTF-8 strings)
+// Column positions are computed in terminal display cells: tabs are expanded to
+// stops every 8 cells, combining marks take no space and East Asian wide
+// characters take two cells, so columns line up as they appear on the screen.
 func ParseAligned(lines []string, nbColumn int) (Parsed, error) {
 	cols, err := columns(lines, nbColumn)
 	if err != nil {
 		return nil, err
 	}
+	return parseByCols(lines, cols), nil
+}
+
+// ParseAlignedAuto parses aligned table like ParseAligned but guesses the number of
+// columns with InferLayout. The layout is returned together with its confidence, so
+// the caller can decide whether the result is reliable enough.
+func ParseAlignedAuto(lines []string) (Parsed, Layout, error) {
+	layout, err := InferLayout(lines)
+	if err != nil {
+		return nil, layout, err
+	}
+	return parseByCols(lines, layout.cols), layout, nil
+}
+
+func parseByCols(lines []string, cols []column) Parsed {
 	result := make([]parsedLine, len(lines))
 	for i, line := range lines {
 		result[i] = parsedLine{
 			parsed:   splitByCols(line, cols),
 			original: line}
 	}
-	return result, nil
+	return result
 }
 
 func splitByCols(line string, cols []column) []string {
+	dl := newDisplayLine(line)
+	width := dl.width()
 	splitted := make([]string, len(cols))
 	for i, c := range cols {
-		if len(line) >= c.to {
-			from := findFrom(line, cols, i)
-			to := findTo(line, cols, i)
-			splitted[i] = line[from:to]
-		} else if len(line) > c.from {
-			from := findFrom(line, cols, i)
-			splitted[i] = line[from:]
+		if width >= c.to {
+			from := findFrom(cols, i)
+			to := findTo(width, cols, i)
+			splitted[i] = dl.slice(from, to)
+		} else if width > c.from {
+			from := findFrom(cols, i)
+			splitted[i] = dl.slice(from, width)
 		} // if none of those two match splitted[i] will contains empty string
 	}
 	return splitted
 }
 
-func findFrom(line string, cols []column, i int) int {
+func findFrom(cols []column, i int) int {
 	prevColumn := 0
 	if i > 0 {
 		prevColumn = cols[i-1].to
 	}
 	from := cols[i].from
 	for {
-		_, w := utf8.DecodeLastRuneInString(line[:from])
 		// we can't extend return current
-		if w == 0 {
+		if from == 0 {
 			return from
 		}
 		// we have reached the line start
-		if from-w <= 0 {
+		if from-1 <= 0 {
 			return 0
 		}
 		// by extending the column we overlapped the previous column, lets return the original from
-		if from-w <= prevColumn {
+		if from-1 <= prevColumn {
 			return from
 		}
-		from -= w
+		from--
 	}
 }
 
-func findTo(line string, cols []column, i int) int {
-	nextColumn := len(line)
+func findTo(width int, cols []column, i int) int {
+	nextColumn := width
 	if i < len(cols)-1 {
 		nextColumn = cols[i+1].from
 	}
 	to := cols[i].to
 	for {
-		_, w := utf8.DecodeRuneInString(line[to:])
 		// we can't extend return current
-		if w == 0 {
+		if to == width {
 			return to
 		}
 		// we have reached the line end, we are in the last column
-		if to+w == len(line) {
-			return len(line)
+		if to+1 == width {
+			return width
 		}
 		// by extending the column we overlapped the next column, lets return the original from
-		if to+w >= nextColumn {
+		if to+1 >= nextColumn {
 			return to
 		}
-		to += w
+		to++
+	}
+}
+
+// ParseAlignedWithHeader parses aligned table starting with the first line matching
+// header predicate. Columns are seeded from positions of the header labels (separated by
+// two or more whitespaces) and widened by data below them, so data can be left, right or
+// centre aligned under its label and may have empty cells. Labels become column names
+// of the result, which contains lines following the header.
+func ParseAlignedWithHeader(lines []string, header func(string) bool) (Table, error) {
+	for i, line := range lines {
+		if header(line) {
+			return ParseAlignedWithHeaderAt(lines, i)
+		}
+	}
+	return Table{}, errors.New("can't find header line")
+}
+
+// ParseAlignedWithHeaderAt behaves like ParseAlignedWithHeader for header at given index
+func ParseAlignedWithHeaderAt(lines []string, index int) (Table, error) {
+	if index < 0 || index >= len(lines) {
+		return Table{}, errors.Errorf("header index %d out of range", index)
+	}
+	labels := extractFields(lines[index])
+	if len(labels) == 0 {
+		return Table{}, errors.Errorf("header line %d is empty", index)
+	}
+	names := make([]string, len(labels))
+	cols := make([]column, len(labels))
+	for i, label := range labels {
+		names[i] = strings.TrimSpace(label.value)
+		cols[i] = column{from: label.from, to: label.To()}
+	}
+	data := lines[index+1:]
+	for _, line := range data {
+		for _, f := range extractFields(line) {
+			i := closestColumn(cols, f)
+			if cols[i].contains(f.from, f.To()) {
+				continue
+			}
+			columnsCopy := append([]column{}, cols...)
+			columnsCopy[i].extend(f.from, f.To())
+			if !columnsOverlap(columnsCopy) {
+				cols = columnsCopy
+			}
+		}
+	}
+	return Table{Parsed: parseByCols(data, cols), columns: names}, nil
+}
+
+// closestColumn returns index of the column sharing the most cells with the field, or the
+// nearest one if the field lies between columns
+func closestColumn(cols []column, f field) int {
+	best, bestScore := 0, math.MinInt32
+	for i, c := range cols {
+		// overlap is positive when column and field intersect, otherwise it is minus
+		// distance between them
+		score := minInt(c.to, f.To()) - maxInt(c.from, f.from)
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
 	}
+	return b
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 )
@@ -11,6 +12,12 @@ import (
 var validSeparationLine = regexp.MustCompile(`^(((-\s)+-?)|(_+))\s*$`)
 var whiteSpaceOnly = regexp.MustCompile(`^(\s*)$`)
 
+// characters drawing lines of ASCII grids (+---+) and Unicode boxes (┌───┐)
+const (
+	horizontalBorders = "-=─━═"
+	verticalBorders   = "|│┃║"
+)
+
 // Key of box table
 type Key struct {
 	Column, Row string
@@ -21,23 +28,23 @@ func (k *Key) String() string {
 	return fmt.Sprintf("(%s,%s)", k.Column, k.Row)
 }
 
-// ParseBoxes source containing input inside boxes
+// ParseBoxes source containing input inside boxes. Boxes can be drawn with dashes
+// (- - -) or underscores, as ASCII grids (+----+----+, also with ==== header separators
+// and psql's ----+---- style) or with Unicode box drawing characters (┌─┬─┐ │ ├─┼─┤).
+// Cells spanning several lines are joined, except in grids which draw separation lines
+// only around the header: there each line is a row. A grid with ==== line below the
+// header separates all its rows, so its single row may span several lines too.
+// The result is keyed by the header cell and the first cell of each row, use ParseBox
+// to keep order of rows or rows sharing the same first cell.
 func ParseBoxes(lines []string, columns int) (map[Key]string, error) {
-	tableLines := limitToTable(lines)
-	if len(tableLines) == 0 {
-		return nil, errors.New("can't extract box")
-	}
-	entries, err := parseTable(tableLines, columns)
+	box, err := ParseBox(lines, columns)
 	if err != nil {
 		return nil, err
 	}
-	if len(entries) == 0 {
-		return nil, errors.New("can't find any entries")
-	}
-	header := entries[0]
+	header := box.Header()
 
 	result := map[Key]string{}
-	for _, e := range entries[1:] {
+	for _, e := range box.Rows() {
 		rowHeader := e[0]
 		for i, rowEntry := range e[1:] {
 			result[Key{header[i+1], rowHeader}] = rowEntry
@@ -46,6 +53,80 @@ func ParseBoxes(lines []string, columns int) (map[Key]string, error) {
 	return result, nil
 }
 
+// Box is a table parsed from a box, its entries are kept in the document order
+type Box struct {
+	entries Parsed
+}
+
+// ParseBox parses the first box found in lines, see ParseBoxes for supported formats
+func ParseBox(lines []string, columns int) (Box, error) {
+	region, ok := nextBox(lines, 0)
+	if !ok {
+		return Box{}, errors.New("can't extract box")
+	}
+	return region.Parse(columns)
+}
+
+// BoxRegion is a box found in the input by FindBoxes
+type BoxRegion struct {
+	// Start and End delimit the box in the input, it spans lines[Start:End]
+	Start, End int
+	// Caption is the closest non empty line above the box (empty if there is none),
+	// heading underlines are skipped
+	Caption string
+	// Lines of the box without empty lines
+	Lines T
+}
+
+// FindBoxes returns all boxes found in lines in the document order. Bordered grids end at
+// their closing border, other boxes need to be separated by at least one line which is
+// not a part of a box (a caption for instance).
+func FindBoxes(lines []string) []BoxRegion {
+	var result []BoxRegion
+	for offset := 0; ; {
+		region, ok := nextBox(lines, offset)
+		if !ok {
+			return result
+		}
+		result = append(result, region)
+		offset = region.End
+	}
+}
+
+// Parse the box, see ParseBoxes for supported formats
+func (r BoxRegion) Parse(columns int) (Box, error) {
+	entries, err := parseTable(r.Lines, columns)
+	if err != nil {
+		return Box{}, err
+	}
+	if len(entries) == 0 {
+		return Box{}, errors.New("can't find any entries")
+	}
+	return Box{entries: entries}, nil
+}
+
+// Header returns the first entry of the box
+func (b Box) Header() []string {
+	head, _ := b.entries.Head()
+	return head
+}
+
+// Rows returns entries following the header
+func (b Box) Rows() [][]string {
+	return b.entries.SkipOneLine().Lines()
+}
+
+// Parsed returns all entries including the header. Original of an entry spanning
+// several lines contains all of them separated by a new line.
+func (b Box) Parsed() Parsed {
+	return b.entries
+}
+
+// Table returns rows of the box with columns named by the header
+func (b Box) Table() Table {
+	return Table{Parsed: b.entries.SkipOneLine(), columns: b.Header()}
+}
+
 type entry []string
 
 func (e entry) isNonEmpty() bool {
@@ -57,37 +138,90 @@ func (e entry) isNonEmpty() bool {
 	return false
 }
 
-func parseTable(table []string, columns int) ([]entry, error) {
-	var current entry
-	var result []entry
-
-	appendEntry := func(e entry) {
-		if e.isNonEmpty() {
-			result = append(result, e)
-		}
-	}
-
+func parseTable(table []string, columns int) (Parsed, error) {
+	var blocks [][]string
+	var current []string
+	styled, grid, bordered, rowSeparators := false, false, false, false
 	for _, line := range table {
 		if isSeparationLine(line) {
-			appendEntry(current)
-			current = make(entry, columns)
+			// the first separation line decides style of the table
+			if !styled {
+				styled = true
+				grid = isGridSeparationLine(line) && !validSeparationLine.MatchString(line)
+				bordered = grid && isBorderedSeparationLine(line)
+			}
+			if current != nil {
+				blocks = append(blocks, current)
+				// a double line below the header is drawn by grids separating all rows
+				if len(blocks) == 1 && strings.ContainsAny(line, "=═") {
+					rowSeparators = true
+				}
+			}
+			current = nil
 			continue
 		}
-		splitted := strings.Split(line, "|")
-		if len(splitted) != columns &&
-			(!onlyFirstColumnHasContent(splitted) || len(splitted) > columns) {
+		current = append(current, line)
+	}
+	if current != nil {
+		blocks = append(blocks, current)
+	}
+	// more than one block below the header means that rows are separated
+	rowSeparators = rowSeparators || len(blocks) > 2
 
-			return nil, errors.Errorf("unexpected number of columns %d, needed %d in %s",
-				len(splitted), columns, line)
+	var result Parsed
+	appendEntry := func(lines []string) error {
+		e := make(entry, columns)
+		for _, line := range lines {
+			splitted := splitBoxRow(line, bordered)
+			if len(splitted) != columns &&
+				(!onlyFirstColumnHasContent(splitted) || len(splitted) > columns) {
+
+				return errors.Errorf("unexpected number of columns %d, needed %d in %s",
+					len(splitted), columns, line)
+			}
+			for i, elem := range splitted {
+				e[i] = join(e[i], strings.Trim(elem, " "))
+			}
+		}
+		if e.isNonEmpty() {
+			result = append(result, parsedLine{original: strings.Join(lines, "\n"), parsed: e})
+		}
+		return nil
+	}
+	for i, block := range blocks {
+		// grids printed by database clients put each row on its own line and draw
+		// separation lines only around the header
+		if !grid || rowSeparators || (i == 0 && len(blocks) > 1) {
+			if err := appendEntry(block); err != nil {
+				return nil, err
+			}
+			continue
 		}
-		for i, elem := range splitted {
-			current[i] = join(current[i], strings.Trim(elem, " "))
+		for _, line := range block {
+			if err := appendEntry([]string{line}); err != nil {
+				return nil, err
+			}
 		}
 	}
-	appendEntry(current)
 	return result, nil
 }
 
+// splitBoxRow splits row into cells, outer borders of bordered tables are removed
+func splitBoxRow(line string, bordered bool) []string {
+	line = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(verticalBorders, r) {
+			return '|'
+		}
+		return r
+	}, line)
+	if bordered {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "|")
+		line = strings.TrimSuffix(line, "|")
+	}
+	return strings.Split(line, "|")
+}
+
 // onlyFirstColumnHasContent returns true iff all but first columns are empty (first can be
 // empty or not)
 func onlyFirstColumnHasContent(row []string) bool {
@@ -107,24 +241,63 @@ func join(left, right string) string {
 	if left == "" {
 		return right
 	}
+	if right == "" {
+		return left
+	}
 	return left + " " + right
 }
 
-func limitToTable(lines []string) []string {
-	var result []string
-	boxStarted := false
-	for _, line := range lines {
-		boxStarted = boxStarted || isSeparationLine(line)
-		if boxStarted {
-			if !isInsideBox(line) {
-				return result
+// nextBox finds the first box starting at or after offset. A bordered grid ends at its
+// closing border: lines without vertical borders and another grid following it are not
+// a part of the box.
+func nextBox(lines []string, offset int) (BoxRegion, bool) {
+	region := BoxRegion{Start: -1}
+	previous := -1
+	bordered, afterBorder := false, false
+	for i := offset; i < len(lines); i++ {
+		line := lines[i]
+		if region.Start < 0 {
+			if !isSeparationLine(line) {
+				if !isWhiteSpace(line) && !isUnderline(line) {
+					previous = i
+				}
+				continue
+			}
+			region.Start = i
+			bordered = isGridSeparationLine(line) && isBorderedSeparationLine(line)
+			// tables printed by psql have no top border, the header is above the first line
+			if previous >= 0 && isGridSeparationLine(line) && !isBorderedSeparationLine(line) &&
+				strings.ContainsAny(lines[previous], verticalBorders) {
+
+				region.Start = previous
+				region.Lines = append(region.Lines, lines[previous])
+				previous = -1
+				for j := region.Start - 1; j >= offset && previous < 0; j-- {
+					if !isWhiteSpace(lines[j]) && !isUnderline(lines[j]) {
+						previous = j
+					}
+				}
 			}
-			if !isWhiteSpace(line) {
-				result = append(result, line)
+			if previous >= 0 {
+				region.Caption = strings.TrimSpace(lines[previous])
 			}
 		}
+		if isWhiteSpace(line) {
+			continue
+		}
+		if bordered {
+			border := isSeparationLine(line)
+			if border && afterBorder || !border && !isBorderedRow(line) {
+				break
+			}
+			afterBorder = border
+		} else if !isInsideBox(line) {
+			break
+		}
+		region.Lines = append(region.Lines, line)
+		region.End = i + 1
 	}
-	return result
+	return region, region.Start >= 0
 }
 
 func isWhiteSpace(line string) bool {
@@ -132,9 +305,75 @@ func isWhiteSpace(line string) bool {
 }
 
 func isInsideBox(line string) bool {
-	return strings.ContainsAny(line, "|-_") || isWhiteSpace(line)
+	return strings.ContainsAny(line, "|-_") || strings.IndexFunc(line, isBoxDrawing) >= 0 ||
+		isWhiteSpace(line) || isSeparationLine(line)
+}
+
+// isUnderline checks whether the line only underlines a heading, like ----- or =====
+func isUnderline(line string) bool {
+	s := strings.TrimSpace(line)
+	return s != "" && strings.Trim(s, "-=_~^*#") == ""
+}
+
+// isBorderedRow checks whether the line starts and ends with a vertical border
+func isBorderedRow(line string) bool {
+	s := []rune(strings.TrimSpace(line))
+	return len(s) > 1 && strings.ContainsRune(verticalBorders, s[0]) &&
+		strings.ContainsRune(verticalBorders, s[len(s)-1])
 }
 
 func isSeparationLine(line string) bool {
-	return validSeparationLine.Match([]byte(line))
+	return validSeparationLine.Match([]byte(line)) || isGridSeparationLine(line)
+}
+
+// isGridSeparationLine matches borders like +----+----+, ----+----, +====+ or ├────┼────┤.
+// A line of dashes without any junction or vertical border (like a heading underline)
+// isn't a border.
+func isGridSeparationLine(line string) bool {
+	s := strings.TrimSpace(line)
+	horizontals, junctions := 0, 0
+	for _, v := range verticalBorders {
+		if strings.HasPrefix(s, string(v)) {
+			s = strings.TrimPrefix(s, string(v))
+			junctions++
+		}
+		if strings.HasSuffix(s, string(v)) {
+			s = strings.TrimSuffix(s, string(v))
+			junctions++
+		}
+	}
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune(horizontalBorders, r):
+			horizontals++
+		case isBoxJunction(r):
+			junctions++
+		default:
+			return false
+		}
+	}
+	return horizontals >= 3 && junctions > 0
+}
+
+// isBorderedSeparationLine checks whether the separation line starts with a corner, so
+// rows of the table are expected to have outer borders
+func isBorderedSeparationLine(line string) bool {
+	s := strings.TrimSpace(line)
+	if s == "" {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return isBoxJunction(r) || strings.ContainsRune(verticalBorders, r)
+}
+
+func isBoxDrawing(r rune) bool {
+	return r >= 0x2500 && r <= 0x257f
+}
+
+// isBoxJunction matches crossings and corners of box borders
+func isBoxJunction(r rune) bool {
+	if r == '+' {
+		return true
+	}
+	return isBoxDrawing(r) && !strings.ContainsRune(horizontalBorders+verticalBorders, r)
 }
@@ -37,3 +37,280 @@ h| H1|H2
 
 	require.Equal(p.T(), expectedResult, result)
 }
+
+func (p *parserSuite) TestParsingASCIIGrid() {
+	input := `
+mysql> select * from balances;
++---------+--------+--------+
+| account | debit  | credit |
++---------+--------+--------+
+| A       | 10.00  | 0.00   |
+| B       | 0.00   | 5.50   |
++---------+--------+--------+
+2 rows in set (0.00 sec)
+`
+	result, err := ParseBoxes(strings.Split(input, "\n"), 3)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), map[Key]string{
+		{"debit", "A"}:  "10.00",
+		{"credit", "A"}: "0.00",
+		{"debit", "B"}:  "0.00",
+		{"credit", "B"}: "5.50",
+	}, result)
+}
+
+func (p *parserSuite) TestParsingPsqlOutput() {
+	input := `
+ account | debit | credit
+---------+-------+--------
+ A       | 10.00 |   0.00
+ B       |  0.00 |   5.50
+(2 rows)
+`
+	result, err := ParseBoxes(strings.Split(input, "\n"), 3)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), map[Key]string{
+		{"debit", "A"}:  "10.00",
+		{"credit", "A"}: "0.00",
+		{"debit", "B"}:  "0.00",
+		{"credit", "B"}: "5.50",
+	}, result)
+}
+
+func (p *parserSuite) TestParsingUnicodeBox() {
+	input := `
+┌─────────┬────────┐
+│ account │ amount │
+╞═════════╪════════╡
+│ A       │ 10.00  │
+├─────────┼────────┤
+│ B       │ 5.50   │
+│         │ EUR    │
+└─────────┴────────┘
+`
+	result, err := ParseBoxes(strings.Split(input, "\n"), 2)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), map[Key]string{
+		{"amount", "A"}: "10.00",
+		{"amount", "B"}: "5.50 EUR",
+	}, result)
+}
+
+func (p *parserSuite) TestParsingGridWithHeaderSeparator() {
+	input := `
++------+----------+
+| Item | Price    |
+| name | in EUR   |
++======+==========+
+| Tea  | 1.50     |
++------+----------+
+| Cake | 2.00     |
+|      | (slice)  |
++------+----------+
+`
+	result, err := ParseBoxes(strings.Split(input, "\n"), 2)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), map[Key]string{
+		{"Price in EUR", "Tea"}:  "1.50",
+		{"Price in EUR", "Cake"}: "2.00 (slice)",
+	}, result)
+}
+
+func (p *parserSuite) TestHeadingUnderlineIsNotABorder() {
+	input := `
+Report
+------
+Some text
+- - - - -
+h| H1|H2
+- - - - -
+1| h1|h1
+`
+	result, err := ParseBoxes(strings.Split(input, "\n"), 3)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), map[Key]string{{"H1", "1"}: "h1", {"H2", "1"}: "h1"}, result)
+}
+
+func (p *parserSuite) TestIsGridSeparationLine() {
+	for _, line := range []string{"+---+", "---+---", "│───│", "├───┼───┤", "║═══║", " |---| "} {
+		require.True(p.T(), isGridSeparationLine(line), line)
+	}
+	for _, line := range []string{"------", "───", "=====", "|--", "+-+", "| a |"} {
+		require.False(p.T(), isGridSeparationLine(line), line)
+	}
+}
+
+func (p *parserSuite) TestParsingGridWithWrappedRow() {
+	input := `
++---+---------+
+| a | b       |
++===+=========+
+| x | long    |
+|   | wrapped |
++---+---------+
+`
+	box, err := ParseBox(strings.Split(input, "\n"), 2)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), [][]string{{"x", "long wrapped"}}, box.Rows())
+}
+
+func (p *parserSuite) TestParsingMySQLRowWithEmptyKey() {
+	input := `
++----+------+
+| id | name |
++----+------+
+| 1  | a    |
+|    | b    |
+| 3  | c    |
++----+------+
+`
+	box, err := ParseBox(strings.Split(input, "\n"), 2)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), [][]string{{"1", "a"}, {"", "b"}, {"3", "c"}}, box.Rows())
+}
+
+func (p *parserSuite) TestParseBoxKeepsOrderAndDuplicates() {
+	input := `
++-------+--------+
+| name  | amount |
++-------+--------+
+| Total | 10.00  |
+| A     | 1.00   |
+| Total | 11.00  |
++-------+--------+
+`
+	box, err := ParseBox(strings.Split(input, "\n"), 2)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), []string{"name", "amount"}, box.Header())
+	require.Equal(p.T(), [][]string{
+		{"Total", "10.00"},
+		{"A", "1.00"},
+		{"Total", "11.00"},
+	}, box.Rows())
+	require.Len(p.T(), box.Parsed(), 4)
+	require.Equal(p.T(), []string{"A", "1.00"}, box.Parsed().FindLine(LineContaining("1.00 ")))
+	require.Equal(p.T(), []string{"name", "amount"}, box.Table().Columns())
+	require.Len(p.T(), box.Table().Lines(), 3)
+}
+
+func (p *parserSuite) TestParseBoxKeepsAllOriginalLines() {
+	input := `
+- - - - -
+h| H1|H2
+1| h1|h1
+`
+	box, err := ParseBox(strings.Split(input, "\n"), 3)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), "h| H1|H2\n1| h1|h1", box.Parsed()[0].original)
+}
+
+func (p *parserSuite) TestFindBoxes() {
+	input := `
+Account 1
++------+--------+
+| day  | amount |
++------+--------+
+| 1    | 10.00  |
++------+--------+
+
+Account 2
+ day | amount
+-----+--------
+ 2   |   5.00
+ 3   |   7.00
+(2 rows)
+`
+	lines := strings.Split(input, "\n")
+	boxes := FindBoxes(lines)
+	require.Len(p.T(), boxes, 2)
+
+	require.Equal(p.T(), "Account 1", boxes[0].Caption)
+	require.Equal(p.T(), 2, boxes[0].Start)
+	require.Equal(p.T(), 7, boxes[0].End)
+	require.Equal(p.T(), "+------+--------+", lines[boxes[0].End-1])
+
+	require.Equal(p.T(), "Account 2", boxes[1].Caption)
+	require.Equal(p.T(), 9, boxes[1].Start)
+	require.Equal(p.T(), 13, boxes[1].End)
+	require.True(p.T(), boxes[1].Lines.Ensure(LineContaining("7.00")))
+
+	box, err := boxes[1].Parse(2)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), [][]string{{"2", "5.00"}, {"3", "7.00"}}, box.Rows())
+}
+
+func (p *parserSuite) TestFindBoxesSeparatedByBlankLine() {
+	input := `
++---+---+
+| a | b |
++---+---+
+| 1 | 2 |
++---+---+
+
++---+---+
+| c | d |
++---+---+
+| 3 | 4 |
++---+---+
+`
+	boxes := FindBoxes(strings.Split(input, "\n"))
+	require.Len(p.T(), boxes, 2)
+	for i, rows := range [][][]string{{{"1", "2"}}, {{"3", "4"}}} {
+		box, err := boxes[i].Parse(2)
+		require.Nil(p.T(), err)
+		require.Equal(p.T(), rows, box.Rows())
+	}
+	require.Equal(p.T(), 6, boxes[0].End)
+	require.Equal(p.T(), 7, boxes[1].Start)
+}
+
+func (p *parserSuite) TestFindBoxesWithCaptionsContainingBorders() {
+	input := `
+Account 2-B
++-----+--------+
+| day | amount |
++-----+--------+
+| 1   | 10.00  |
++-----+--------+
+Period 2018-01 | closed
++-----+--------+
+| day | amount |
++-----+--------+
+| 2   | 5.00   |
++-----+--------+
+`
+	boxes := FindBoxes(strings.Split(input, "\n"))
+	require.Len(p.T(), boxes, 2)
+	require.Equal(p.T(), "Account 2-B", boxes[0].Caption)
+	require.Equal(p.T(), "Period 2018-01 | closed", boxes[1].Caption)
+	box, err := boxes[0].Parse(2)
+	require.Nil(p.T(), err)
+	require.Equal(p.T(), [][]string{{"1", "10.00"}}, box.Rows())
+}
+
+func (p *parserSuite) TestFindBoxesWithUnderlinedCaption() {
+	input := `
+Balances
+--------
++-----+--------+
+| day | amount |
++-----+--------+
+| 1   | 10.00  |
++-----+--------+
+
+Totals
+======
+
+ day | amount
+-----+--------
+ 1   |  10.00
+`
+	boxes := FindBoxes(strings.Split(input, "\n"))
+	require.Len(p.T(), boxes, 2)
+	require.Equal(p.T(), "Balances", boxes[0].Caption)
+	require.Equal(p.T(), "Totals", boxes[1].Caption)
+}
+
+func (p *parserSuite) TestFindBoxesWhenThereIsNone() {
+	require.Empty(p.T(), FindBoxes([]string{"a", "b"}))
+}
@@ -2,6 +2,7 @@ package table
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
 	"reflect"
 	"strings"
@@ -104,3 +105,156 @@ func FromStrStrSlice(lines [][]string, comma ...string) Parsed {
 	}
 	return p
 }
+
+// CSVColumn is a column expected in CSV header. Names are compared case insensitively
+// with whitespace collapsed, Aliases are alternative names of the column.
+type CSVColumn struct {
+	Name    string
+	Aliases []string
+}
+
+// CSVHeader describes header expected by ForeachRecord
+type CSVHeader struct {
+	Columns []CSVColumn
+	// AllowExtra accepts header containing columns which are not listed in Columns
+	AllowExtra bool
+}
+
+// HeaderError is returned when the header doesn't match expected columns. Header is
+// the row which was the closest match (if any).
+type HeaderError struct {
+	Header  []string
+	Missing []string
+	Extra   []string
+}
+
+// Error implements error
+func (e *HeaderError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing columns %q", e.Missing))
+	}
+	if len(e.Extra) > 0 {
+		problems = append(problems, fmt.Sprintf("unexpected columns %q", e.Extra))
+	}
+	return "can't match header: " + strings.Join(problems, ", ")
+}
+
+// Record is a CSV row whose fields are addressable by header names
+type Record struct {
+	index map[string]int
+	row   []string
+}
+
+// Get returns field of the column with given name or alias. Columns present in the
+// header but not listed in CSVHeader can be accessed by their header text.
+func (r Record) Get(name string) (string, bool) {
+	i, ok := r.index[normalizeColumnName(name)]
+	if !ok || i >= len(r.row) {
+		return "", false
+	}
+	return r.row[i], true
+}
+
+// Fields returns all fields of the row
+func (r Record) Fields() []string { return r.row }
+
+// ForeachRecord finds table starting with the first row containing all expected columns
+// (in any order) and ending with whitespace row, each row is forwarded to f as a Record.
+// If the header can't be found or has unexpected columns *HeaderError is returned.
+func (r CSV) ForeachRecord(header CSVHeader, f func(Record)) error {
+	var best *HeaderError
+	var index map[string]int
+	for {
+		row, err := r.Reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "can't read csv")
+		}
+		if index == nil {
+			var match *HeaderError
+			index, match = matchHeader(header, row)
+			if index != nil {
+				continue
+			}
+			if best == nil || len(match.Missing) < len(best.Missing) {
+				best = match
+			}
+			if len(match.Missing) == 0 {
+				return match
+			}
+			continue
+		}
+		if stringsOnlyWhitespace(row) {
+			return nil
+		}
+		f(Record{index: index, row: row})
+	}
+	if index != nil {
+		return nil
+	}
+	if best == nil {
+		best = &HeaderError{Missing: columnNames(header.Columns)}
+	}
+	return best
+}
+
+// matchHeader returns index of columns if the row matches the header, otherwise it
+// returns error describing the difference
+func matchHeader(header CSVHeader, row []string) (map[string]int, *HeaderError) {
+	positions := map[string]int{}
+	for i, name := range row {
+		if n := normalizeColumnName(name); n != "" {
+			if _, ok := positions[n]; !ok {
+				positions[n] = i
+			}
+		}
+	}
+	index := map[string]int{}
+	for n, i := range positions {
+		index[n] = i
+	}
+	match := &HeaderError{Header: row}
+	known := map[int]bool{}
+	for _, c := range header.Columns {
+		i, ok := -1, false
+		for _, name := range append([]string{c.Name}, c.Aliases...) {
+			if i, ok = positions[normalizeColumnName(name)]; ok {
+				break
+			}
+		}
+		if !ok {
+			match.Missing = append(match.Missing, c.Name)
+			continue
+		}
+		known[i] = true
+		for _, name := range append([]string{c.Name}, c.Aliases...) {
+			index[normalizeColumnName(name)] = i
+		}
+	}
+	if !header.AllowExtra {
+		for i, name := range row {
+			if !known[i] && strings.TrimSpace(name) != "" {
+				match.Extra = append(match.Extra, name)
+			}
+		}
+	}
+	if len(match.Missing) > 0 || len(match.Extra) > 0 {
+		return nil, match
+	}
+	return index, nil
+}
+
+func normalizeColumnName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func columnNames(columns []CSVColumn) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
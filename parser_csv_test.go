@@ -0,0 +1,132 @@
+package table
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type csvSuite struct{ suite.Suite }
+
+func TestCSV(t *testing.T) { suite.Run(t, new(csvSuite)) }
+
+func newCSV(s string) CSV {
+	reader := csv.NewReader(strings.NewReader(s))
+	reader.FieldsPerRecord = -1
+	return CSV{Reader: reader}
+}
+
+var statementHeader = CSVHeader{
+	Columns: []CSVColumn{
+		{Name: "Date"},
+		{Name: "Amount", Aliases: []string{"Value"}},
+	},
+	AllowExtra: true,
+}
+
+func (s *csvSuite) TestForeachRecord() {
+	input := `Statement,2018
+,
+ booking  DATE,Description,VALUE
+01.02.2018,Coffee,4.50
+02.02.2018,Rent,1200.00
+,,
+Total,,1204.50
+`
+	var dates, amounts, descriptions []string
+	err := newCSV(input).ForeachRecord(CSVHeader{
+		Columns: []CSVColumn{
+			{Name: "Date", Aliases: []string{"Booking date"}},
+			{Name: "Amount", Aliases: []string{"Value"}},
+		},
+		AllowExtra: true,
+	}, func(r Record) {
+		date, _ := r.Get("date")
+		amount, _ := r.Get("Amount")
+		description, _ := r.Get("description")
+		dates = append(dates, date)
+		amounts = append(amounts, amount)
+		descriptions = append(descriptions, description)
+	})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"01.02.2018", "02.02.2018"}, dates)
+	require.Equal(s.T(), []string{"4.50", "1200.00"}, amounts)
+	require.Equal(s.T(), []string{"Coffee", "Rent"}, descriptions)
+}
+
+func (s *csvSuite) TestForeachRecordMissingColumn() {
+	input := "Date,Description\n01.02.2018,Coffee\n"
+	err := newCSV(input).ForeachRecord(statementHeader, func(Record) {})
+	require.NotNil(s.T(), err)
+	headerErr, ok := err.(*HeaderError)
+	require.True(s.T(), ok)
+	require.Equal(s.T(), []string{"Amount"}, headerErr.Missing)
+	require.Equal(s.T(), []string{"Date", "Description"}, headerErr.Header)
+}
+
+func (s *csvSuite) TestForeachRecordExtraColumn() {
+	input := "Date,Fee,Amount\n01.02.2018,0.10,4.50\n"
+	header := statementHeader
+	header.AllowExtra = false
+	err := newCSV(input).ForeachRecord(header, func(Record) {})
+	require.NotNil(s.T(), err)
+	headerErr, ok := err.(*HeaderError)
+	require.True(s.T(), ok)
+	require.Empty(s.T(), headerErr.Missing)
+	require.Equal(s.T(), []string{"Fee"}, headerErr.Extra)
+	require.Equal(s.T(), `can't match header: unexpected columns ["Fee"]`, err.Error())
+}
+
+func (s *csvSuite) TestRecordGetUnknownColumn() {
+	input := "Date,Amount\n01.02.2018\n"
+	var found []bool
+	err := newCSV(input).ForeachRecord(statementHeader, func(r Record) {
+		_, ok := r.Get("Amount")
+		found = append(found, ok)
+		_, ok = r.Get("Balance")
+		found = append(found, ok)
+	})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []bool{false, false}, found)
+}
+
+func (s *csvSuite) TestSniffCSV() {
+	testcases := []struct {
+		input   string
+		dialect Dialect
+	}{
+		{
+			input:   "Date,Amount\n01.02.2018,4.50\n02.02.2018,12.00\n",
+			dialect: Dialect{Comma: ',', HasHeader: true},
+		}, {
+			input:   "\xef\xbb\xbfDatum;Betrag\n01.02.2018;\"4,50\"\n02.02.2018;\"12,00\"\n",
+			dialect: Dialect{Comma: ';', Quoted: true, HasHeader: true, BOM: true},
+		}, {
+			input:   "# exported 2018\nname\tcity\nJohn\tParis\nAnn\tRome\n",
+			dialect: Dialect{Comma: '\t', Comment: '#'},
+		}, {
+			input:   "id|note\n1|5\" screen\n2|3\" screen\n",
+			dialect: Dialect{Comma: '|', LazyQuotes: true, HasHeader: true},
+		},
+	}
+	for _, t := range testcases {
+		_, dialect, err := SniffCSV(strings.NewReader(t.input))
+		require.Nil(s.T(), err)
+		require.Equal(s.T(), t.dialect, dialect, t.input)
+	}
+}
+
+func (s *csvSuite) TestSniffCSVReturnsConfiguredReader() {
+	input := "\xef\xbb\xbfDate;Amount\n01.02.2018;4,50\n\n"
+	reader, _, err := SniffCSV(strings.NewReader(input), 8)
+	require.Nil(s.T(), err)
+	var rows [][]string
+	err = reader.ForeachLine([]string{"Date", "Amount"}, func(row []string) {
+		rows = append(rows, row)
+	})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{{"01.02.2018", "4,50"}}, rows)
+}
@@ -0,0 +1,320 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FieldType is a type of fixed width field
+type FieldType string
+
+// Types of fixed width fields
+const (
+	// FieldString is any text, it's the default type
+	FieldString FieldType = "string"
+	// FieldInteger is a whole number with optional sign, leading zeros are removed
+	FieldInteger FieldType = "integer"
+	// FieldDecimal is a number with optional sign and decimal point. When the field has
+	// a scale the decimal point is implied and inserted before the last Scale digits.
+	FieldDecimal FieldType = "decimal"
+	// FieldDate is a date in the Format of the field, it's kept as written
+	FieldDate FieldType = "date"
+)
+
+// Trim tells from which side of the field padding is removed
+type Trim string
+
+// Sides of the field padding is removed from
+const (
+	TrimBoth  Trim = "both"
+	TrimLeft  Trim = "left"
+	TrimRight Trim = "right"
+	TrimNone  Trim = "none"
+)
+
+// FixedField is a field of a fixed width record. Start is the byte position of the
+// first character counted from 1 as in published record layouts.
+type FixedField struct {
+	Name   string    `json:"name"`
+	Start  int       `json:"start"`
+	Length int       `json:"length"`
+	Type   FieldType `json:"type,omitempty"`
+	// Format of FieldDate as Go time layout, e.g. 20060102
+	Format string `json:"format,omitempty"`
+	// Scale is the number of implied decimal places of FieldDecimal
+	Scale int `json:"scale,omitempty"`
+	// Pad contains padding characters, space by default
+	Pad string `json:"pad,omitempty"`
+	// Trim tells which padding is removed, TrimBoth by default. Digits are never
+	// removed from the right of integer and decimal fields, so that zero padding doesn't
+	// strip their trailing zeros.
+	Trim     Trim `json:"trim,omitempty"`
+	Required bool `json:"required,omitempty"`
+}
+
+func (f FixedField) end() int { return f.Start - 1 + f.Length }
+
+// FixedSchema describes layout of fixed width records
+type FixedSchema struct {
+	Fields []FixedField `json:"fields"`
+	// Strict rejects lines which are shorter or longer than the record
+	Strict bool `json:"strict,omitempty"`
+}
+
+// ReadFixedSchema reads schema from JSON like
+//
+//	{"fields": [{"name": "id", "start": 1, "length": 6, "type": "integer", "pad": "0"}]}
+func ReadFixedSchema(r io.Reader) (FixedSchema, error) {
+	var s FixedSchema
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return s, errors.Wrap(err, "can't read fixed width schema")
+	}
+	return s, s.Validate()
+}
+
+// Validate checks that fields have unique names, valid positions and known types
+func (s FixedSchema) Validate() error {
+	if len(s.Fields) == 0 {
+		return errors.New("fixed width schema has no fields")
+	}
+	names := map[string]bool{}
+	for _, f := range s.Fields {
+		switch {
+		case f.Name == "":
+			return errors.Errorf("field at %d has no name", f.Start)
+		case names[f.Name]:
+			return errors.Errorf("duplicate field %q", f.Name)
+		case f.Start < 1 || f.Length < 1:
+			return errors.Errorf("field %q has invalid position %d and length %d",
+				f.Name, f.Start, f.Length)
+		case f.Scale < 0 || (f.Scale > 0 && f.Type != FieldDecimal):
+			return errors.Errorf("field %q has invalid scale %d", f.Name, f.Scale)
+		}
+		switch f.Type {
+		case "", FieldString, FieldInteger, FieldDecimal:
+		case FieldDate:
+			if f.Format == "" {
+				return errors.Errorf("date field %q has no format", f.Name)
+			}
+		default:
+			return errors.Errorf("field %q has unknown type %q", f.Name, f.Type)
+		}
+		switch f.Trim {
+		case "", TrimBoth, TrimLeft, TrimRight, TrimNone:
+		default:
+			return errors.Errorf("field %q has unknown trim %q", f.Name, f.Trim)
+		}
+		names[f.Name] = true
+	}
+	return nil
+}
+
+// Columns returns names of the fields
+func (s FixedSchema) Columns() []string {
+	columns := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		columns[i] = f.Name
+	}
+	return columns
+}
+
+// length of the record, the end of the last field
+func (s FixedSchema) length() int {
+	var length int
+	for _, f := range s.Fields {
+		length = maxInt(length, f.end())
+	}
+	return length
+}
+
+// FixedError is a validation error of a field. Line is counted from 1 and Offset is
+// the byte offset of the field in the line counted from 0.
+type FixedError struct {
+	Line   int
+	Offset int
+	Field  string
+	Value  string
+	Reason string
+}
+
+// Error implements error
+func (e *FixedError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+	}
+	return fmt.Sprintf("line %d, offset %d: field %q: %s (value %q)",
+		e.Line, e.Offset, e.Field, e.Reason, e.Value)
+}
+
+// FixedErrors contains validation errors of all invalid lines
+type FixedErrors []*FixedError
+
+// Error implements error
+func (e FixedErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ParseFixed parses fixed width records described by the schema. Columns of the table
+// are named by the fields, blank lines are skipped. Lines with invalid fields are not
+// returned, their errors are collected into FixedErrors returned along with the
+// valid records.
+func ParseFixed(lines []string, schema FixedSchema) (Table, error) {
+	t := Table{Parsed: Parsed{}, columns: schema.Columns()}
+	if err := schema.Validate(); err != nil {
+		return t, err
+	}
+	var errs FixedErrors
+	length := schema.length()
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if schema.Strict && len(line) != length {
+			errs = append(errs, &FixedError{Line: i + 1, Offset: minInt(len(line), length),
+				Reason: fmt.Sprintf("record length is %d, expected %d", len(line), length)})
+			continue
+		}
+		parsed, lineErrs := parseFixedLine(line, schema.Fields)
+		for _, err := range lineErrs {
+			err.Line = i + 1
+		}
+		if len(lineErrs) > 0 {
+			errs = append(errs, lineErrs...)
+			continue
+		}
+		t.Parsed = append(t.Parsed, parsedLine{original: line, parsed: parsed})
+	}
+	if len(errs) > 0 {
+		return t, errs
+	}
+	return t, nil
+}
+
+func parseFixedLine(line string, fields []FixedField) ([]string, FixedErrors) {
+	var errs FixedErrors
+	parsed := make([]string, len(fields))
+	for i, f := range fields {
+		from, to := minInt(f.Start-1, len(line)), minInt(f.end(), len(line))
+		raw := line[from:to]
+		value, reason := fixedValue(raw, f)
+		if reason != "" {
+			errs = append(errs, &FixedError{Offset: f.Start - 1, Field: f.Name, Value: raw,
+				Reason: reason})
+		}
+		parsed[i] = value
+	}
+	return parsed, errs
+}
+
+// fixedValue removes padding of the raw value and checks its type. Reason of the
+// failure is returned for invalid values.
+func fixedValue(raw string, f FixedField) (string, string) {
+	pad := f.Pad
+	if pad == "" {
+		pad = " "
+	}
+	rightPad := pad
+	if f.Type == FieldInteger || f.Type == FieldDecimal {
+		rightPad = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return -1
+			}
+			return r
+		}, pad)
+	}
+	var value string
+	switch f.Trim {
+	case TrimNone:
+		value = raw
+	case TrimLeft:
+		value = strings.TrimLeft(raw, pad)
+	case TrimRight:
+		value = strings.TrimRight(raw, rightPad)
+	default:
+		value = strings.TrimRight(strings.TrimLeft(raw, pad), rightPad)
+	}
+	if strings.TrimSpace(value) == "" {
+		if strings.TrimSpace(raw) != "" && f.Type == FieldInteger {
+			// number consisting of zero padding only
+			return "0", ""
+		}
+		if strings.TrimSpace(raw) != "" && f.Type == FieldDecimal {
+			return fixedDecimal("0", f.Scale)
+		}
+		if f.Required {
+			return "", "value is required"
+		}
+		return "", ""
+	}
+	switch f.Type {
+	case FieldInteger:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return value, "not an integer"
+		}
+		return strconv.FormatInt(n, 10), ""
+	case FieldDecimal:
+		return fixedDecimal(strings.TrimSpace(value), f.Scale)
+	case FieldDate:
+		if _, err := time.Parse(f.Format, value); err != nil {
+			return value, fmt.Sprintf("not a date in format %q", f.Format)
+		}
+	}
+	return value, ""
+}
+
+// fixedDecimal validates decimal number and inserts implied decimal point
+func fixedDecimal(value string, scale int) (string, string) {
+	sign := ""
+	digits := value
+	if digits[0] == '+' || digits[0] == '-' {
+		if digits[0] == '-' {
+			sign = "-"
+		}
+		digits = digits[1:]
+	}
+	intPart, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		if scale > 0 {
+			return value, "decimal point in a field with implied decimals"
+		}
+		intPart, fraction = digits[:i], digits[i+1:]
+	}
+	if !allDigits(intPart) || !allDigits(fraction) || intPart+fraction == "" {
+		return value, "not a decimal number"
+	}
+	if scale > 0 {
+		for len(intPart) <= scale {
+			intPart = "0" + intPart
+		}
+		intPart, fraction = intPart[:len(intPart)-scale], intPart[len(intPart)-scale:]
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if fraction != "" {
+		return sign + intPart + "." + fraction, ""
+	}
+	return sign + intPart, ""
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type fixedSuite struct{ suite.Suite }
+
+func TestFixed(t *testing.T) { suite.Run(t, new(fixedSuite)) }
+
+var payrollSchema = FixedSchema{Fields: []FixedField{
+	{Name: "Employee", Start: 1, Length: 6, Type: FieldInteger, Pad: "0", Required: true},
+	{Name: "Name", Start: 7, Length: 12},
+	{Name: "Paid", Start: 19, Length: 8, Type: FieldDate, Format: "20060102"},
+	{Name: "Amount", Start: 27, Length: 9, Type: FieldDecimal, Scale: 2, Pad: "0", Trim: TrimLeft},
+	{Name: "Code", Start: 36, Length: 3, Pad: "*", Trim: TrimRight},
+}}
+
+func (s *fixedSuite) TestParseFixed() {
+	t, err := ParseFixed(strings.Split(
+		"000042John Smith  20180201000123456A**\n"+
+			"\n"+
+			"000007Ann         20180202-00000050B1*\n"+
+			"000000X           20180203000000000", "\n"), payrollSchema)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Employee", "Name", "Paid", "Amount", "Code"}, t.Columns())
+	require.Equal(s.T(), [][]string{
+		{"42", "John Smith", "20180201", "1234.56", "A"},
+		{"7", "Ann", "20180202", "-0.50", "B1"},
+		{"0", "X", "20180203", "0.00", ""},
+	}, t.Lines())
+	require.Equal(s.T(), "000007Ann         20180202-00000050B1*", t.Parsed[1].original)
+}
+
+func (s *fixedSuite) TestZeroPaddingKeepsTrailingZeros() {
+	schema := FixedSchema{Fields: []FixedField{
+		{Name: "id", Start: 1, Length: 6, Type: FieldInteger, Pad: "0"},
+		{Name: "amount", Start: 7, Length: 7, Type: FieldDecimal, Scale: 2, Pad: "0"},
+		{Name: "count", Start: 14, Length: 4, Type: FieldInteger, Pad: "0 ", Trim: TrimRight},
+	}}
+	t, err := ParseFixed([]string{"0001000012500100 ", "0000000000000"}, schema)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{{"100", "125.00", "100"}, {"0", "0.00", ""}}, t.Lines())
+}
+
+func (s *fixedSuite) TestValidationErrors() {
+	t, err := ParseFixed(strings.Split(
+		"00004xJohn Smith  20180231000123456A**\n"+
+			"000007Ann         20180202-00000050B1*\n"+
+			"      Nobody      201802020000001.0", "\n"), payrollSchema)
+	require.Equal(s.T(), [][]string{{"7", "Ann", "20180202", "-0.50", "B1"}}, t.Lines())
+	errs, ok := err.(FixedErrors)
+	require.True(s.T(), ok)
+	require.Equal(s.T(), FixedErrors{
+		{Line: 1, Offset: 0, Field: "Employee", Value: "00004x", Reason: "not an integer"},
+		{Line: 1, Offset: 18, Field: "Paid", Value: "20180231",
+			Reason: `not a date in format "20060102"`},
+		{Line: 3, Offset: 0, Field: "Employee", Value: "      ", Reason: "value is required"},
+		{Line: 3, Offset: 26, Field: "Amount", Value: "0000001.0",
+			Reason: "decimal point in a field with implied decimals"},
+	}, errs)
+	require.Equal(s.T(),
+		`line 1, offset 0: field "Employee": not an integer (value "00004x")`, errs[0].Error())
+}
+
+func (s *fixedSuite) TestStrict() {
+	schema := FixedSchema{Fields: []FixedField{
+		{Name: "Code", Start: 1, Length: 2},
+		{Name: "Text", Start: 3, Length: 3, Trim: TrimNone},
+	}, Strict: true}
+	t, err := ParseFixed([]string{"AB x \r", "CD", "EFghij"}, schema)
+	require.Equal(s.T(), [][]string{{"AB", " x "}}, t.Lines())
+	require.Equal(s.T(), FixedErrors{
+		{Line: 2, Offset: 2, Reason: "record length is 2, expected 5"},
+		{Line: 3, Offset: 5, Reason: "record length is 6, expected 5"},
+	}, err)
+	require.Equal(s.T(), "line 2: record length is 2, expected 5", err.(FixedErrors)[0].Error())
+
+	schema.Strict = false
+	t, err = ParseFixed([]string{"CD", "EFghij"}, schema)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{{"CD", ""}, {"EF", "ghi"}}, t.Lines())
+}
+
+func (s *fixedSuite) TestReadFixedSchema() {
+	schema, err := ReadFixedSchema(strings.NewReader(`{
+		"strict": true,
+		"fields": [
+			{"name": "id", "start": 1, "length": 6, "type": "integer", "pad": "0"},
+			{"name": "paid", "start": 7, "length": 8, "type": "date", "format": "20060102"},
+			{"name": "amount", "start": 15, "length": 5, "type": "decimal", "scale": 2}
+		]}`))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), FixedSchema{Strict: true, Fields: []FixedField{
+		{Name: "id", Start: 1, Length: 6, Type: FieldInteger, Pad: "0"},
+		{Name: "paid", Start: 7, Length: 8, Type: FieldDate, Format: "20060102"},
+		{Name: "amount", Start: 15, Length: 5, Type: FieldDecimal, Scale: 2},
+	}}, schema)
+
+	for _, invalid := range []string{
+		`{"fields": []}`,
+		`{"fields": [{"start": 1, "length": 2}]}`,
+		`{"fields": [{"name": "a", "start": 0, "length": 2}]}`,
+		`{"fields": [{"name": "a", "start": 1, "length": 2}, {"name": "a", "start": 3, "length": 2}]}`,
+		`{"fields": [{"name": "a", "start": 1, "length": 2, "type": "money"}]}`,
+		`{"fields": [{"name": "a", "start": 1, "length": 2, "type": "date"}]}`,
+		`{"fields": [{"name": "a", "start": 1, "length": 2, "scale": 2}]}`,
+		`{"fields": [{"name": "a", "start": 1, "length": 2, "trim": "middle"}]}`,
+		`{"fields": [`,
+	} {
+		_, err := ReadFixedSchema(strings.NewReader(invalid))
+		require.NotNil(s.T(), err, invalid)
+	}
+}
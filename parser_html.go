@@ -1,51 +1,215 @@
 package table
 
 import (
-	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/pkg/errors"
 )
 
-// ParseFromHTML table encoded inside string
+// Section of HTML table a row comes from
+type Section int
+
+// Sections of HTML table, rows outside of thead and tfoot belong to the body
+const (
+	Body Section = iota
+	Head
+	Foot
+)
+
+// HTMLTable is a table parsed from HTML. Rows are kept in the document order.
+type HTMLTable struct {
+	id, caption string
+	rows        Parsed
+	cells       [][]Cell
+	sections    []Section
+	// firstRowIsHeader is set when the first row consists of th cells only
+	firstRowIsHeader bool
+}
+
+// ParseFromHTML tables encoded inside string. Rows of all tables in the document are
+// returned, a nested table follows the table containing it. Both td and th cells are
+// returned, slots covered by colspan or rowspan are empty.
 func ParseFromHTML(s string) (Parsed, error) {
+	d, err := ParseHTMLDocument(s)
+	if err != nil {
+		return Parsed{}, err
+	}
 	p := Parsed{}
+	for _, t := range d.Tables() {
+		p = append(p, t.Parsed()...)
+	}
+	return p, nil
+}
+
+// ParseHTMLTable parses the first table encoded inside string keeping track of table
+// sections. Cells are placed into a grid following the HTML table processing model, fill
+// tells how slots covered by colspan or rowspan are filled (FillEmpty by default).
+func ParseHTMLTable(s string, fill ...SpanFill) (HTMLTable, error) {
+	d, err := ParseHTMLDocument(s, fill...)
+	if err != nil {
+		return HTMLTable{rows: Parsed{}}, err
+	}
+	return d.Table(0)
+}
+
+// HTMLDocument gives access to tables of a HTML document
+type HTMLDocument struct {
+	doc  *goquery.Document
+	fill SpanFill
+}
+
+// ParseHTMLDocument parses HTML encoded inside string, fill tells how slots covered by
+// colspan or rowspan are filled in its tables (FillEmpty by default)
+func ParseHTMLDocument(s string, fill ...SpanFill) (HTMLDocument, error) {
+	d := HTMLDocument{fill: FillEmpty}
+	if len(fill) > 0 {
+		d.fill = fill[0]
+	}
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
 	if err != nil {
-		return p, errors.Wrap(err, "can't parse html")
+		return d, errors.Wrap(err, "can't parse html")
+	}
+	d.doc = doc
+	return d, nil
+}
+
+// Tables returns all tables of the document in the document order. Nested tables are
+// parsed on their own: a nested table is a separate element of the result and its text
+// is not a part of the cell containing it.
+func (d HTMLDocument) Tables() []HTMLTable {
+	var result []HTMLTable
+	d.doc.Find("table").Each(func(_ int, s *goquery.Selection) {
+		result = append(result, newHTMLTable(s, d.fill))
+	})
+	return result
+}
+
+// Table returns table at given index of the Tables result
+func (d HTMLDocument) Table(index int) (HTMLTable, error) {
+	tables := d.doc.Find("table")
+	if index < 0 || index >= tables.Length() {
+		return HTMLTable{rows: Parsed{}}, errors.Errorf(
+			"can't find table %d, document contains %d tables", index, tables.Length())
 	}
-	doc.Find("table tr").Each(func(i int, s *goquery.Selection) {
-		if err != nil {
-			return
+	return newHTMLTable(tables.Eq(index), d.fill), nil
+}
+
+// TableBySelector returns the first table matching CSS selector or, if the selector
+// matches another element, the first table inside of it
+func (d HTMLDocument) TableBySelector(selector string) (HTMLTable, error) {
+	for _, node := range d.doc.Find(selector).Nodes {
+		s := d.doc.FindNodes(node)
+		if !s.Is("table") {
+			s = s.Find("table").First()
 		}
-		line := []string{}
-		s.Find("td").Each(func(i int, s *goquery.Selection) {
-			if err != nil {
-				return
-			}
-			colspan, err2 := extractColspan(s)
-			if err2 != nil {
-				err = err2
-			}
-			line = append(line, strings.TrimSpace(s.Text()))
-			for i := 1; i < colspan; i++ {
-				line = append(line, "")
-			}
-		})
-		p = append(p, parsedLine{
+		if s.Length() > 0 {
+			return newHTMLTable(s, d.fill), nil
+		}
+	}
+	return HTMLTable{rows: Parsed{}}, errors.Errorf("can't find table matching %q", selector)
+}
+
+// TableByID returns table with given id attribute
+func (d HTMLDocument) TableByID(id string) (HTMLTable, error) {
+	return d.tableMatching(func(t HTMLTable) bool { return t.ID() == id },
+		"can't find table with id %q", id)
+}
+
+// TableByCaption returns the first table whose caption matches the predicate
+func (d HTMLDocument) TableByCaption(predicate func(string) bool) (HTMLTable, error) {
+	return d.tableMatching(func(t HTMLTable) bool { return predicate(t.Caption()) },
+		"can't find table with matching caption")
+}
+
+// TableByHeader returns the first table whose column names match the predicate
+func (d HTMLDocument) TableByHeader(predicate func([]string) bool) (HTMLTable, error) {
+	return d.tableMatching(func(t HTMLTable) bool { return predicate(t.Columns()) },
+		"can't find table with matching header")
+}
+
+func (d HTMLDocument) tableMatching(
+	predicate func(HTMLTable) bool, format string, args ...interface{}) (HTMLTable, error) {
+
+	for _, t := range d.Tables() {
+		if predicate(t) {
+			return t, nil
+		}
+	}
+	return HTMLTable{rows: Parsed{}}, errors.Errorf(format, args...)
+}
+
+func newHTMLTable(s *goquery.Selection, fill SpanFill) HTMLTable {
+	t := HTMLTable{rows: Parsed{}}
+	t.id, _ = s.Attr("id")
+	t.caption = strings.TrimSpace(s.ChildrenFiltered("caption").First().Text())
+	first := s.Find("tr").First().ChildrenFiltered("td, th")
+	t.firstRowIsHeader = first.Length() > 0 && first.Length() == first.Filter("th").Length()
+	grid := newHTMLGrid(s, fill)
+	for y, line := range grid.rows() {
+		t.rows = append(t.rows, parsedLine{
 			original: strings.Join(line, "\t"),
 			parsed:   line,
 		})
-	})
-	return p, err
+		t.sections = append(t.sections, grid.sections[y])
+	}
+	t.cells = grid.cells()
+	return t
+}
+
+// ID returns id attribute of the table
+func (t HTMLTable) ID() string { return t.id }
+
+// Caption returns text of the table caption
+func (t HTMLTable) Caption() string { return t.caption }
+
+// Parsed returns all rows of the table
+func (t HTMLTable) Parsed() Parsed { return t.rows }
+
+// Cells returns rows of the table with text normalised, attributes and links of each
+// cell. Slots covered by colspan or rowspan are filled according to the SpanFill.
+func (t HTMLTable) Cells() [][]Cell { return t.cells }
+
+// Section returns section of the i-th row
+func (t HTMLTable) Section(i int) Section { return t.sections[i] }
+
+// Head returns rows of thead
+func (t HTMLTable) Head() Parsed { return t.inSection(Head) }
+
+// Body returns rows which are neither in thead nor in tfoot
+func (t HTMLTable) Body() Parsed { return t.inSection(Body) }
+
+// Foot returns rows of tfoot
+func (t HTMLTable) Foot() Parsed { return t.inSection(Foot) }
+
+func (t HTMLTable) inSection(section Section) Parsed {
+	result := Parsed{}
+	for i, line := range t.rows {
+		if t.sections[i] == section {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// Columns returns names of the columns. They are taken from the last row of thead or,
+// when there is no thead, from the first row if it consists of th cells only.
+func (t HTMLTable) Columns() []string {
+	if head := t.Head(); len(head) > 0 {
+		return head[len(head)-1].parsed
+	}
+	if t.firstRowIsHeader {
+		return t.rows[0].parsed
+	}
+	return nil
 }
 
-func extractColspan(s *goquery.Selection) (int, error) {
-	val, ok := s.Attr("colspan")
-	if !ok {
-		return 1, nil
+// Table returns body rows with named columns. When the header is the first body row
+// it is not repeated among the rows.
+func (t HTMLTable) Table() Table {
+	body := t.Body()
+	if len(t.Head()) == 0 && t.firstRowIsHeader {
+		body = body.SkipOneLine()
 	}
-	n, err := strconv.Atoi(val)
-	return n, errors.Wrap(err, "can't parse colspan")
+	return Table{Parsed: body, columns: t.Columns()}
 }
@@ -43,7 +43,7 @@ func (s *parseHTMLSuite) TestParseFromHTML() {
 `
 
 	var expectedParsedHTML = Parsed{
-		{original: "", parsed: []string{}}, // ParseFromHTML doesn't parse row headers
+		{original: "Location\tDelivery\tPrice", parsed: []string{"Location", "Delivery", "Price"}},
 		{original: "Delhi\tJanuary\t100", parsed: []string{"Delhi", "January", "100"}},
 		{original: "Pune\tFebruary\t80", parsed: []string{"Pune", "February", "80"}},
 		{original: "Pune\tNo prices\t", parsed: []string{"Pune", "No prices", ""}},
@@ -56,3 +56,255 @@ func (s *parseHTMLSuite) TestParseFromHTML() {
 	require.Nil(s.T(), err)
 	require.Equal(s.T(), expectedParsedHTML, parsedHTML)
 }
+
+func (s *parseHTMLSuite) TestParseHTMLTableSections() {
+
+	// Given
+	const htmlStr = `
+<table>
+  <thead>
+    <tr><th>Location</th><th>Price</th></tr>
+  </thead>
+  <tfoot>
+    <tr><th>Total</th><td>180</td></tr>
+  </tfoot>
+  <tbody>
+    <tr><th>Delhi</th><td>100</td></tr>
+    <tr><th>Pune</th><td>80</td></tr>
+  </tbody>
+</table>
+`
+
+	// When
+	table, err := ParseHTMLTable(htmlStr)
+
+	// Then
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{
+		{"Location", "Price"},
+		{"Total", "180"},
+		{"Delhi", "100"},
+		{"Pune", "80"},
+	}, table.Parsed().Lines())
+	require.Equal(s.T(), Head, table.Section(0))
+	require.Equal(s.T(), Foot, table.Section(1))
+	require.Equal(s.T(), Body, table.Section(2))
+	require.Equal(s.T(), [][]string{{"Total", "180"}}, table.Foot().Lines())
+	require.Equal(s.T(), []string{"Location", "Price"}, table.Columns())
+	require.Equal(s.T(), [][]string{{"Delhi", "100"}, {"Pune", "80"}}, table.Table().Lines())
+}
+
+func (s *parseHTMLSuite) TestParseHTMLTableHeaderWithoutThead() {
+
+	// Given
+	const htmlStr = `
+<table>
+  <tr><th>Location</th><th>Price</th></tr>
+  <tr><td>Delhi</td><td>100</td></tr>
+</table>
+`
+
+	// When
+	table, err := ParseHTMLTable(htmlStr)
+
+	// Then
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Location", "Price"}, table.Table().Columns())
+	require.Equal(s.T(), [][]string{{"Delhi", "100"}}, table.Table().Lines())
+}
+
+func (s *parseHTMLSuite) TestParseHTMLTableRowspan() {
+
+	// Given
+	const htmlStr = `
+<table>
+  <tr><th>Location</th><th>Month</th><th>Price</th></tr>
+  <tr><td rowspan="2">Pune</td><td>January</td><td>80</td></tr>
+  <tr><td>February</td><td>90</td></tr>
+  <tr><td>Delhi</td><td colspan="2px">No prices</td></tr>
+</table>
+`
+
+	// When
+	empty, err := ParseHTMLTable(htmlStr)
+	require.Nil(s.T(), err)
+	repeated, err := ParseHTMLTable(htmlStr, FillRepeat)
+	require.Nil(s.T(), err)
+
+	// Then
+	require.Equal(s.T(), [][]string{
+		{"Location", "Month", "Price"},
+		{"Pune", "January", "80"},
+		{"", "February", "90"},
+		{"Delhi", "No prices", ""},
+	}, empty.Parsed().Lines())
+	require.Equal(s.T(), [][]string{
+		{"Location", "Month", "Price"},
+		{"Pune", "January", "80"},
+		{"Pune", "February", "90"},
+		{"Delhi", "No prices", "No prices"},
+	}, repeated.Parsed().Lines())
+}
+
+func (s *parseHTMLSuite) TestParseHTMLTableRowspanStaysInRowGroup() {
+
+	// Given
+	const htmlStr = `
+<table>
+  <thead>
+    <tr><th rowspan="5">Year</th><th colspan="2">Amount</th></tr>
+    <tr><th>in</th><th>out</th></tr>
+  </thead>
+  <tbody>
+    <tr><td rowspan="0">2018</td><td>1</td><td>2</td></tr>
+    <tr><td>3</td><td rowspan="3">4</td></tr>
+    <tr><td>5</td></tr>
+  </tbody>
+</table>
+`
+
+	// When
+	table, err := ParseHTMLTable(htmlStr, FillRepeat)
+
+	// Then
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{
+		{"Year", "Amount", "Amount"},
+		{"Year", "in", "out"},
+		{"2018", "1", "2"},
+		{"2018", "3", "4"},
+		{"2018", "5", "4"},
+	}, table.Parsed().Lines())
+	require.Equal(s.T(), []string{"Year", "in", "out"}, table.Columns())
+}
+
+const documentWithTables = `
+<html>
+  <body>
+    <table id="accounts">
+      <caption>Accounts</caption>
+      <tr><th>Account</th><th>Details</th></tr>
+      <tr>
+        <td>A</td>
+        <td>
+          opened
+          <table class="details">
+            <tr><th>Date</th><th>Amount</th></tr>
+            <tr><td>01.02.2018</td><td>10</td></tr>
+          </table>
+        </td>
+      </tr>
+    </table>
+    <div class="summary">
+      <table>
+        <caption>Summary for 2018</caption>
+        <tr><th>Total</th><td>10</td></tr>
+      </table>
+    </div>
+  </body>
+</html>
+`
+
+func (s *parseHTMLSuite) TestHTMLDocumentTables() {
+
+	// When
+	doc, err := ParseHTMLDocument(documentWithTables)
+	require.Nil(s.T(), err)
+	tables := doc.Tables()
+
+	// Then
+	require.Len(s.T(), tables, 3)
+	require.Equal(s.T(), [][]string{{"Account", "Details"}, {"A", "opened"}},
+		tables[0].Parsed().Lines())
+	require.Equal(s.T(), [][]string{{"Date", "Amount"}, {"01.02.2018", "10"}},
+		tables[1].Parsed().Lines())
+	require.Equal(s.T(), "accounts", tables[0].ID())
+	require.Equal(s.T(), "Summary for 2018", tables[2].Caption())
+
+	parsed, err := ParseFromHTML(documentWithTables)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), parsed, 5)
+}
+
+func (s *parseHTMLSuite) TestHTMLDocumentSelectTable() {
+
+	// Given
+	doc, err := ParseHTMLDocument(documentWithTables)
+	require.Nil(s.T(), err)
+
+	// When
+	byIndex, err := doc.Table(1)
+	require.Nil(s.T(), err)
+	bySelector, err := doc.TableBySelector("div.summary")
+	require.Nil(s.T(), err)
+	byID, err := doc.TableByID("accounts")
+	require.Nil(s.T(), err)
+	byCaption, err := doc.TableByCaption(LineContaining("Summary"))
+	require.Nil(s.T(), err)
+	byHeader, err := doc.TableByHeader(func(columns []string) bool {
+		return containsString(columns, "Amount")
+	})
+	require.Nil(s.T(), err)
+
+	// Then
+	require.Equal(s.T(), []string{"Date", "Amount"}, byIndex.Columns())
+	require.Equal(s.T(), "Summary for 2018", bySelector.Caption())
+	require.Equal(s.T(), "Accounts", byID.Caption())
+	require.Equal(s.T(), [][]string{{"Total", "10"}}, byCaption.Parsed().Lines())
+	require.Equal(s.T(), [][]string{{"01.02.2018", "10"}}, byHeader.Table().Lines())
+}
+
+func (s *parseHTMLSuite) TestHTMLDocumentTableNotFound() {
+
+	// Given
+	doc, err := ParseHTMLDocument(documentWithTables)
+	require.Nil(s.T(), err)
+
+	// When
+	_, errIndex := doc.Table(3)
+	_, errSelector := doc.TableBySelector("table.missing")
+	_, errID := doc.TableByID("missing")
+
+	// Then
+	require.NotNil(s.T(), errIndex)
+	require.NotNil(s.T(), errSelector)
+	require.NotNil(s.T(), errID)
+}
+
+func (s *parseHTMLSuite) TestHTMLTableCells() {
+
+	// Given
+	const htmlStr = `
+<table>
+  <tr><th>Document</th><th>Amount</th></tr>
+  <tr>
+    <td title="Invoice 42"><a href="/invoices/42.pdf">Invoice</a>
+      <a href="/invoices/42.xml">XML</a></td>
+    <td data-value="1234.5" class="num">1 234,50<br>EUR</td>
+  </tr>
+  <tr><td colspan="2"><p>Paid</p>   in   full</td></tr>
+</table>
+`
+
+	// When
+	table, err := ParseHTMLTable(htmlStr)
+
+	// Then
+	require.Nil(s.T(), err)
+	cells := table.Cells()
+	require.Len(s.T(), cells, 3)
+	require.Equal(s.T(), "Invoice XML", cells[1][0].Text)
+	require.Equal(s.T(), []Link{
+		{Href: "/invoices/42.pdf", Text: "Invoice"},
+		{Href: "/invoices/42.xml", Text: "XML"},
+	}, cells[1][0].Links)
+	title, ok := cells[1][0].Attr("title")
+	require.True(s.T(), ok)
+	require.Equal(s.T(), "Invoice 42", title)
+	require.Equal(s.T(), "1 234,50\nEUR", cells[1][1].Text)
+	require.Equal(s.T(), "1234.5", cells[1][1].Attrs["data-value"])
+	require.Equal(s.T(), "Paid\nin full", cells[2][0].Text)
+	require.Equal(s.T(), Cell{}, cells[2][1])
+	// plain view is unchanged
+	require.Equal(s.T(), "1 234,50EUR", table.Parsed().Lines()[1][1])
+}
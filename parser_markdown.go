@@ -0,0 +1,171 @@
+package table
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var markdownDelimiterCell = regexp.MustCompile(`^:?-+:?$`)
+
+// Alignment of a table column
+type Alignment int
+
+// Alignments of Markdown table columns, AlignDefault is used when the delimiter row
+// has no colon
+const (
+	AlignDefault Alignment = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+// MarkdownTable is a pipe table (GitHub flavoured Markdown) found in the input. Its
+// rows follow the header and the delimiter row, originals are the Markdown lines.
+type MarkdownTable struct {
+	Table
+	// Start and End delimit the table in the input, it spans lines[Start:End]
+	Start, End int
+
+	alignments []Alignment
+}
+
+// Alignments returns alignment of each column as defined by the delimiter row
+func (t MarkdownTable) Alignments() []Alignment { return t.alignments }
+
+// ParseMarkdown returns the first pipe table found in lines
+func ParseMarkdown(lines []string) (MarkdownTable, error) {
+	t, ok := nextMarkdownTable(lines, 0)
+	if !ok {
+		return t, errors.New("can't find markdown table")
+	}
+	return t, nil
+}
+
+// ParseMarkdownTables returns all pipe tables found in lines in the document order
+func ParseMarkdownTables(lines []string) []MarkdownTable {
+	var result []MarkdownTable
+	for offset := 0; ; {
+		t, ok := nextMarkdownTable(lines, offset)
+		if !ok {
+			return result
+		}
+		result = append(result, t)
+		offset = t.End
+	}
+}
+
+func nextMarkdownTable(lines []string, offset int) (MarkdownTable, bool) {
+	for i := offset; i+1 < len(lines); i++ {
+		if !strings.Contains(lines[i], "|") || !strings.Contains(lines[i+1], "|") {
+			continue
+		}
+		header := splitMarkdownRow(lines[i])
+		alignments, ok := markdownAlignments(lines[i+1])
+		if !ok || len(alignments) != len(header) {
+			continue
+		}
+		t := MarkdownTable{
+			Table:      Table{Parsed: Parsed{}, columns: header},
+			Start:      i,
+			End:        i + 2,
+			alignments: alignments,
+		}
+		for _, line := range lines[i+2:] {
+			if isWhiteSpace(line) || !strings.Contains(line, "|") {
+				break
+			}
+			row := splitMarkdownRow(line)
+			// rows are fitted to the header, excess cells are ignored
+			for len(row) < len(header) {
+				row = append(row, "")
+			}
+			t.Parsed = append(t.Parsed, parsedLine{original: line, parsed: row[:len(header)]})
+			t.End++
+		}
+		return t, true
+	}
+	return MarkdownTable{}, false
+}
+
+func markdownAlignments(line string) ([]Alignment, bool) {
+	cells := splitMarkdownRow(line)
+	alignments := make([]Alignment, len(cells))
+	for i, cell := range cells {
+		if !markdownDelimiterCell.MatchString(cell) {
+			return nil, false
+		}
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			alignments[i] = AlignCenter
+		case left:
+			alignments[i] = AlignLeft
+		case right:
+			alignments[i] = AlignRight
+		}
+	}
+	return alignments, len(cells) > 0
+}
+
+// splitMarkdownRow splits row into trimmed cells. Leading and trailing pipes are
+// optional, escaped pipes (\|) and pipes inside of code spans are a part of the cell.
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimSpace(line)
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case c == '`':
+			n := backtickRun(line, i)
+			end := closingBacktickRun(line, i+n, n)
+			if end < 0 {
+				cell.WriteString(line[i : i+n])
+				i += n - 1
+				continue
+			}
+			cell.WriteString(strings.Replace(line[i:end], `\|`, "|", -1))
+			i = end - 1
+		case c == '|':
+			if i > 0 {
+				cells = append(cells, strings.TrimSpace(cell.String()))
+			}
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+	if rest := strings.TrimSpace(cell.String()); rest != "" || !strings.HasSuffix(line, "|") {
+		cells = append(cells, rest)
+	}
+	return cells
+}
+
+func backtickRun(s string, from int) int {
+	n := 0
+	for from+n < len(s) && s[from+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// closingBacktickRun returns end of the run of exactly n backticks starting at or after
+// from, or -1 if there is none
+func closingBacktickRun(s string, from, n int) int {
+	for i := from; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		run := backtickRun(s, i)
+		if run == n {
+			return i + run
+		}
+		i += run
+	}
+	return -1
+}
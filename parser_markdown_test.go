@@ -0,0 +1,57 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type markdownSuite struct{ suite.Suite }
+
+func TestMarkdown(t *testing.T) { suite.Run(t, new(markdownSuite)) }
+
+const runbook = `# Runbook
+
+| Command | Meaning | Timeout |
+|:--------|:-------:|--------:|
+| ` + "`a | b`" + ` | pipe in code | 10 |
+| grep a\|b | escaped pipe | 5 |
+| ls |
+
+Name | Value
+--- | ---
+x | 1 | ignored
+text after
+`
+
+func (s *markdownSuite) TestParseMarkdown() {
+	t, err := ParseMarkdown(strings.Split(runbook, "\n"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Command", "Meaning", "Timeout"}, t.Columns())
+	require.Equal(s.T(), []Alignment{AlignLeft, AlignCenter, AlignRight}, t.Alignments())
+	require.Equal(s.T(), [][]string{
+		{"`a | b`", "pipe in code", "10"},
+		{"grep a|b", "escaped pipe", "5"},
+		{"ls", "", ""},
+	}, t.Lines())
+	require.Equal(s.T(), 2, t.Start)
+	require.Equal(s.T(), 7, t.End)
+	require.Equal(s.T(), []string{"grep a|b", "escaped pipe", "5"},
+		t.FindLine(LineContaining("grep")))
+	require.Len(s.T(), t.TakeTo(LineContaining("ls")), 2)
+}
+
+func (s *markdownSuite) TestParseMarkdownTables() {
+	tables := ParseMarkdownTables(T(strings.Split(runbook, "\n")))
+	require.Len(s.T(), tables, 2)
+	require.Equal(s.T(), []string{"Name", "Value"}, tables[1].Columns())
+	require.Equal(s.T(), []Alignment{AlignDefault, AlignDefault}, tables[1].Alignments())
+	require.Equal(s.T(), [][]string{{"x", "1"}}, tables[1].Lines())
+}
+
+func (s *markdownSuite) TestParseMarkdownWithoutTable() {
+	_, err := ParseMarkdown([]string{"| a | b |", "| c | d |"})
+	require.NotNil(s.T(), err)
+}
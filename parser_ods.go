@@ -0,0 +1,337 @@
+package table
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// namespaces of OpenDocument elements and attributes
+const (
+	odsOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsTableNS  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsTextNS   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// Spreadsheet is an OpenDocument spreadsheet (ODS)
+type Spreadsheet struct {
+	sheets []odsSheet
+}
+
+type odsSheet struct {
+	name  string
+	cells []odsCell
+	spans []odsSpan
+}
+
+type odsCell struct {
+	row, col int
+	value    string
+}
+
+// odsSpan is a merged range from row, col to the last row and column (inclusive)
+type odsSpan struct {
+	row, col, lastRow, lastCol int
+}
+
+// OpenODS reads spreadsheet from the file
+func OpenODS(filename string) (*Spreadsheet, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read ods")
+	}
+	return ReadODS(bytes.NewReader(b), int64(len(b)))
+}
+
+// ReadODS reads spreadsheet from the reader
+func ReadODS(r io.ReaderAt, size int64) (*Spreadsheet, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't open ods")
+	}
+	for _, f := range z.File {
+		if f.Name != "content.xml" {
+			continue
+		}
+		content, err := f.Open()
+		if err != nil {
+			return nil, errors.Wrap(err, "can't open content.xml")
+		}
+		defer content.Close() // nolint: errcheck
+		sheets, err := readODSContent(xml.NewDecoder(content))
+		if err != nil {
+			return nil, errors.Wrap(err, "can't parse content.xml")
+		}
+		return &Spreadsheet{sheets: sheets}, nil
+	}
+	return nil, errors.New("can't find content.xml in ods")
+}
+
+// Sheets returns names of the sheets in the document order
+func (s *Spreadsheet) Sheets() []string {
+	names := make([]string, len(s.sheets))
+	for i, sheet := range s.sheets {
+		names[i] = sheet.name
+	}
+	return names
+}
+
+// Sheet returns used range of the sheet (the smallest rectangle containing all non
+// empty cells). Repeated rows and columns are expanded, dates are returned as 2006-01-02
+// or 2006-01-02 15:04:05, times as 15:04:05, booleans as TRUE or FALSE and numbers,
+// currencies and percentages as stored. Paragraphs of a text cell are separated by new
+// lines. Slots covered by a merged cell are filled according to fill (FillEmpty by
+// default).
+func (s *Spreadsheet) Sheet(name string, fill ...SpanFill) (Parsed, error) {
+	for _, sheet := range s.sheets {
+		if sheet.name != name {
+			continue
+		}
+		grid := newSheetGrid()
+		for _, c := range sheet.cells {
+			grid.set(c.row, c.col, c.value)
+		}
+		for _, span := range sheet.spans {
+			grid.merge(span.row, span.col, span.lastRow, span.lastCol, spanFillOf(fill))
+		}
+		return grid.parsed(), nil
+	}
+	return nil, errors.Errorf("can't find sheet %q", name)
+}
+
+func readODSContent(d *xml.Decoder) ([]odsSheet, error) {
+	var sheets []odsSheet
+	var sheet *odsSheet
+	var row []odsCell
+	var rowSpans []odsSpan
+	y, x, rowRepeat := 0, 0, 1
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return sheets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != odsTableNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				if sheet != nil {
+					// tables nested in cells are not sheets
+					if err := d.Skip(); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				sheets = append(sheets, odsSheet{name: odsAttr(t, odsTableNS, "name")})
+				sheet = &sheets[len(sheets)-1]
+				y = 0
+			case "table-row":
+				row, rowSpans, x = nil, nil, 0
+				rowRepeat = odsCount(t, "number-rows-repeated")
+			case "table-cell", "covered-table-cell":
+				repeat := odsCount(t, "number-columns-repeated")
+				value, err := readODSCell(d, t)
+				if err != nil {
+					return nil, err
+				}
+				if t.Name.Local == "covered-table-cell" {
+					// content of covered cells is hidden behind the merged cell
+					value = ""
+				}
+				columns := odsCount(t, "number-columns-spanned")
+				rows := odsCount(t, "number-rows-spanned")
+				for i := 0; i < repeat && value != ""; i++ {
+					row = append(row, odsCell{col: x + i, value: value})
+					if columns > 1 || rows > 1 {
+						rowSpans = append(rowSpans, odsSpan{
+							col: x + i, lastRow: rows - 1, lastCol: x + i + columns - 1})
+					}
+				}
+				x += repeat
+			}
+		case xml.EndElement:
+			if t.Name.Space != odsTableNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				sheet = nil
+			case "table-row":
+				// empty rows are often repeated up to the end of the sheet, they are
+				// only counted
+				for i := 0; i < rowRepeat && len(row) > 0; i++ {
+					for _, c := range row {
+						sheet.cells = append(sheet.cells, odsCell{row: y + i, col: c.col, value: c.value})
+					}
+					for _, s := range rowSpans {
+						sheet.spans = append(sheet.spans, odsSpan{
+							row: y + i, col: s.col, lastRow: y + i + s.lastRow, lastCol: s.lastCol})
+					}
+				}
+				y += rowRepeat
+			}
+		}
+	}
+}
+
+func odsAttr(e xml.StartElement, space, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// odsCount reads positive count attribute of the table namespace, 1 by default
+func odsCount(e xml.StartElement, name string) int {
+	n, err := strconv.Atoi(odsAttr(e, odsTableNS, name))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// readODSCell consumes content of the cell and returns its value
+func readODSCell(d *xml.Decoder, cell xml.StartElement) (string, error) {
+	text, err := readODSText(d)
+	if err != nil {
+		return "", err
+	}
+	switch odsAttr(cell, odsOfficeNS, "value-type") {
+	case "float", "percentage", "currency":
+		return odsAttr(cell, odsOfficeNS, "value"), nil
+	case "date":
+		return formatODSDate(odsAttr(cell, odsOfficeNS, "date-value")), nil
+	case "time":
+		return formatODSDuration(odsAttr(cell, odsOfficeNS, "time-value")), nil
+	case "boolean":
+		if odsAttr(cell, odsOfficeNS, "boolean-value") == "true" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	}
+	return text, nil
+}
+
+// readODSText reads paragraphs up to the end of the current element. Spaces, tabs and
+// line breaks encoded as elements are restored, annotations and nested tables are
+// skipped.
+func readODSText(d *xml.Decoder) (string, error) {
+	var b strings.Builder
+	depth, paragraphs, inParagraph := 0, 0, 0
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsOfficeNS && t.Name.Local == "annotation",
+				t.Name.Space == odsTableNS && t.Name.Local == "table":
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+				continue
+			case t.Name.Space == odsTextNS && (t.Name.Local == "p" || t.Name.Local == "h"):
+				if paragraphs > 0 {
+					b.WriteByte('\n')
+				}
+				paragraphs++
+				inParagraph++
+			case t.Name.Space == odsTextNS && t.Name.Local == "s":
+				n, err := strconv.Atoi(odsAttr(t, odsTextNS, "c"))
+				if err != nil || n < 1 {
+					n = 1
+				}
+				b.WriteString(strings.Repeat(" ", n))
+			case t.Name.Space == odsTextNS && t.Name.Local == "tab":
+				b.WriteByte('\t')
+			case t.Name.Space == odsTextNS && t.Name.Local == "line-break":
+				b.WriteByte('\n')
+			}
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return b.String(), nil
+			}
+			depth--
+			if t.Name.Space == odsTextNS && (t.Name.Local == "p" || t.Name.Local == "h") {
+				inParagraph--
+			}
+		case xml.CharData:
+			if inParagraph > 0 {
+				b.Write(t)
+			}
+		}
+	}
+}
+
+// formatODSDate formats date value (2006-01-02 or 2006-01-02T15:04:05 with optional
+// fraction of second), unknown formats are kept as stored
+func formatODSDate(value string) string {
+	if !strings.Contains(value, "T") {
+		return value
+	}
+	t, err := time.Parse("2006-01-02T15:04:05.999999999", value)
+	if err != nil {
+		return value
+	}
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// formatODSDuration formats time value stored as ISO 8601 duration (PT12H30M00S) as
+// hours, minutes and seconds, unknown formats are kept as stored
+func formatODSDuration(value string) string {
+	rest := strings.TrimPrefix(value, "P")
+	if len(rest) == len(value) {
+		return value
+	}
+	var seconds float64
+	units := map[byte]float64{'D': 24 * 60 * 60, 'H': 60 * 60, 'M': 60, 'S': 1}
+	for rest != "" {
+		if rest[0] == 'T' {
+			rest = rest[1:]
+			continue
+		}
+		end := strings.IndexAny(rest, "DHMS")
+		if end < 1 {
+			return value
+		}
+		n, err := strconv.ParseFloat(rest[:end], 64)
+		if err != nil {
+			return value
+		}
+		seconds += n * units[rest[end]]
+		rest = rest[end+1:]
+	}
+	return formatSeconds(seconds)
+}
+
+// formatSeconds formats duration as hours, minutes and seconds like 25:30:00, hours are
+// not wrapped at a day
+func formatSeconds(seconds float64) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	total := int(seconds + 0.5)
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, total/3600, total/60%60, total%60)
+}
@@ -0,0 +1,120 @@
+package table
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type odsSuite struct{ suite.Suite }
+
+func TestODS(t *testing.T) { suite.Run(t, new(odsSuite)) }
+
+var statementODS = zipFiles(map[string]string{
+	"mimetype": "application/vnd.oasis.opendocument.spreadsheet",
+	"content.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content
+    xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+    xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+    xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+ <office:body><office:spreadsheet>
+  <table:table table:name="Statement">
+   <table:table-column table:number-columns-repeated="1024"/>
+   <table:table-row table:number-rows-repeated="2">
+    <table:table-cell table:number-columns-repeated="1024"/>
+   </table:table-row>
+   <table:table-row>
+    <table:table-cell/>
+    <table:table-cell office:value-type="string" table:number-columns-spanned="3">
+     <text:p>Statement 2018</text:p>
+    </table:table-cell>
+    <table:covered-table-cell table:number-columns-repeated="2" office:value-type="string">
+     <text:p>hidden</text:p>
+    </table:covered-table-cell>
+   </table:table-row>
+   <table:table-header-rows>
+    <table:table-row>
+     <table:table-cell/>
+     <table:table-cell office:value-type="string"><text:p>Date</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Amount</text:p></table:table-cell>
+     <table:table-cell office:value-type="string"><text:p>Note</text:p></table:table-cell>
+    </table:table-row>
+   </table:table-header-rows>
+   <table:table-row>
+    <table:table-cell/>
+    <table:table-cell office:value-type="date" office:date-value="2018-02-01"><text:p>01.02.18</text:p></table:table-cell>
+    <table:table-cell office:value-type="currency" office:currency="EUR" office:value="4.5"><text:p>4,50 €</text:p></table:table-cell>
+    <table:table-cell office:value-type="string" table:number-rows-spanned="2">
+     <office:annotation><text:p>comment</text:p></office:annotation>
+     <text:p>Coffee<text:s text:c="2"/><text:span>shop</text:span></text:p>
+     <text:p>second line</text:p>
+    </table:table-cell>
+   </table:table-row>
+   <table:table-row>
+    <table:table-cell table:number-columns-repeated="2"/>
+    <table:table-cell office:value-type="float" office:value="-12"/>
+    <table:covered-table-cell/>
+   </table:table-row>
+   <table:table-row table:number-rows-repeated="2">
+    <table:table-cell/>
+    <table:table-cell office:value-type="date" office:date-value="2018-02-02T12:30:00"/>
+    <table:table-cell office:value-type="float" office:value="0"/>
+    <table:table-cell office:value-type="boolean" office:boolean-value="true"/>
+   </table:table-row>
+   <table:table-row table:number-rows-repeated="1048570">
+    <table:table-cell table:number-columns-repeated="1024"/>
+   </table:table-row>
+  </table:table>
+  <table:table table:name="Times">
+   <table:table-row>
+    <table:table-cell office:value-type="time" office:time-value="PT12H30M00S"/>
+    <table:table-cell office:value-type="time" office:time-value="PT36H00M05.6S"/>
+    <table:table-cell office:value-type="percentage" office:value="0.25"/>
+   </table:table-row>
+  </table:table>
+ </office:spreadsheet></office:body>
+</office:document-content>`,
+})
+
+func (s *odsSuite) TestReadODS() {
+	ods, err := ReadODS(bytes.NewReader(statementODS), int64(len(statementODS)))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Statement", "Times"}, ods.Sheets())
+
+	statement, err := ods.Sheet("Statement")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{
+		{"Statement 2018", "", ""},
+		{"Date", "Amount", "Note"},
+		{"2018-02-01", "4.5", "Coffee  shop\nsecond line"},
+		{"", "-12", ""},
+		{"2018-02-02 12:30:00", "0", "TRUE"},
+		{"2018-02-02 12:30:00", "0", "TRUE"},
+	}, statement.Lines())
+
+	statement, err = ods.Sheet("Statement", FillRepeat)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Statement 2018", "Statement 2018", "Statement 2018"},
+		statement.Lines()[0])
+	require.Equal(s.T(), []string{"", "-12", "Coffee  shop\nsecond line"},
+		statement.Lines()[3])
+
+	times, err := ods.Sheet("Times")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{{"12:30:00", "36:00:06", "0.25"}}, times.Lines())
+
+	_, err = ods.Sheet("Missing")
+	require.NotNil(s.T(), err)
+}
+
+func (s *odsSuite) TestReadInvalidODS() {
+	noContent := zipFiles(map[string]string{"mimetype": "application/vnd.oasis.opendocument.spreadsheet"})
+	_, err := ReadODS(bytes.NewReader(noContent), int64(len(noContent)))
+	require.NotNil(s.T(), err)
+
+	invalid := zipFiles(map[string]string{"content.xml": "<office:document-content><table:table>"})
+	_, err = ReadODS(bytes.NewReader(invalid), int64(len(invalid)))
+	require.NotNil(s.T(), err)
+}
@@ -0,0 +1,389 @@
+package table
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	rstGridBorder      = regexp.MustCompile(`^\s*\+-[-+]*\+\s*$`)
+	rstGridHeaderLine  = regexp.MustCompile(`^\s*\+=[=+]*\+\s*$`)
+	rstSimpleBorder    = regexp.MustCompile(`^\s*=+(\s+=+)*\s*$`)
+	rstSimpleUnderline = regexp.MustCompile(`^\s*-+(\s+-+)*\s*$`)
+)
+
+// ParseRSTGrid parses the first reStructuredText grid table found in lines:
+//
+//	+--------+--------+
+//	| Header | Header |
+//	+========+========+
+//	| cell   | cell   |
+//	+--------+        |
+//	| cell   |        |
+//	+--------+--------+
+//
+// Cells can span several lines (they are joined with a space), columns and rows. Slots
+// covered by a spanning cell are filled according to fill (FillEmpty by default). Rows
+// above the ==== line are joined to column names, original of each row contains its
+// lines separated by a new line.
+func ParseRSTGrid(lines []string, fill ...SpanFill) (Table, error) {
+	start := -1
+	for i, line := range lines {
+		if rstGridBorder.MatchString(line) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return Table{}, errors.New("can't find grid table")
+	}
+	indent := len(lines[start]) - len(strings.TrimLeft(lines[start], " \t"))
+	end := start
+	for end < len(lines) && len(lines[end]) > indent &&
+		strings.ContainsAny(lines[end][indent:indent+1], "+|") {
+
+		end++
+	}
+	g := newRSTGrid(lines[start:end], indent)
+	cells, err := g.scan()
+	if err != nil {
+		return Table{}, err
+	}
+	return g.table(lines[start:end], cells, spanFillOf(fill)), nil
+}
+
+func spanFillOf(fill []SpanFill) SpanFill {
+	if len(fill) > 0 {
+		return fill[0]
+	}
+	return FillEmpty
+}
+
+// rstGrid is a grid table as a rectangle of display cells, block contains rune starting
+// at each cell and zero in the second cell of wide runes
+type rstGrid struct {
+	lines         []displayLine
+	block         [][]rune
+	height, width int
+	// headerLine is the index of ==== line or -1
+	headerLine int
+}
+
+// rstCell is a cell of grid table delimited by its corners
+type rstCell struct {
+	top, left, bottom, right int
+	text                     string
+}
+
+func newRSTGrid(lines []string, indent int) *rstGrid {
+	g := &rstGrid{headerLine: -1}
+	for i, line := range lines {
+		l := newDisplayLine(strings.TrimRight(line[indent:], " \t"))
+		runes := make([]rune, l.width())
+		for c := range runes {
+			if c == 0 || l.offsets[c] != l.offsets[c-1] {
+				runes[c], _ = utf8.DecodeRuneInString(l.text[l.offsets[c]:])
+			}
+		}
+		if len(runes) > g.width {
+			g.width = len(runes)
+		}
+		g.lines = append(g.lines, l)
+		g.block = append(g.block, runes)
+		if i > 0 && g.headerLine < 0 && rstGridHeaderLine.MatchString(line) {
+			g.headerLine = i
+		}
+	}
+	for i, runes := range g.block {
+		for len(runes) < g.width {
+			runes = append(runes, ' ')
+		}
+		g.block[i] = runes
+	}
+	g.height = len(g.block)
+	return g
+}
+
+// scan finds cells starting from the top left corner, each found cell gives corners of
+// its right and bottom neighbours
+func (g *rstGrid) scan() ([]rstCell, error) {
+	var cells []rstCell
+	corners := [][2]int{{0, 0}}
+	done := map[[2]int]bool{}
+	for len(corners) > 0 {
+		sort.Slice(corners, func(i, j int) bool {
+			return corners[i][0] < corners[j][0] ||
+				(corners[i][0] == corners[j][0] && corners[i][1] < corners[j][1])
+		})
+		corner := corners[0]
+		corners = corners[1:]
+		if done[corner] {
+			continue
+		}
+		done[corner] = true
+		top, left := corner[0], corner[1]
+		bottom, right, ok := g.scanCell(top, left)
+		if !ok {
+			continue
+		}
+		cells = append(cells, rstCell{top, left, bottom, right, g.cellText(top, left, bottom, right)})
+		if right < g.width-1 {
+			corners = append(corners, [2]int{top, right})
+		}
+		if bottom < g.height-1 {
+			corners = append(corners, [2]int{bottom, left})
+		}
+	}
+	if len(cells) == 0 {
+		return nil, errors.New("can't find any cell of grid table")
+	}
+	return cells, nil
+}
+
+func (g *rstGrid) scanCell(top, left int) (int, int, bool) {
+	if g.block[top][left] != '+' {
+		return 0, 0, false
+	}
+	for right := left + 1; right < g.width; right++ {
+		switch g.block[top][right] {
+		case '+':
+			if bottom, ok := g.scanDown(top, left, right); ok {
+				return bottom, right, true
+			}
+		case '-', '=':
+		default:
+			return 0, 0, false
+		}
+	}
+	return 0, 0, false
+}
+
+func (g *rstGrid) scanDown(top, left, right int) (int, bool) {
+	for bottom := top + 1; bottom < g.height; bottom++ {
+		switch g.block[bottom][right] {
+		case '+':
+			if g.scanLeft(top, left, bottom, right) {
+				return bottom, true
+			}
+		case '|':
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+func (g *rstGrid) scanLeft(top, left, bottom, right int) bool {
+	for i := right - 1; i > left; i-- {
+		if !strings.ContainsRune("-=+", g.block[bottom][i]) {
+			return false
+		}
+	}
+	if g.block[bottom][left] != '+' {
+		return false
+	}
+	for j := bottom - 1; j > top; j-- {
+		if !strings.ContainsRune("|+", g.block[j][left]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *rstGrid) cellText(top, left, bottom, right int) string {
+	var text string
+	for j := top + 1; j < bottom; j++ {
+		l := g.lines[j]
+		text = join(text, l.slice(minInt(left+1, l.width()), minInt(right, l.width())))
+	}
+	return text
+}
+
+// table places cells into rows and columns delimited by all cell borders
+func (g *rstGrid) table(lines []string, cells []rstCell, fill SpanFill) Table {
+	rowBorders, colBorders := map[int]bool{}, map[int]bool{}
+	for _, c := range cells {
+		rowBorders[c.top], rowBorders[c.bottom] = true, true
+		colBorders[c.left], colBorders[c.right] = true, true
+	}
+	rows, cols := sortedKeys(rowBorders), sortedKeys(colBorders)
+	grid := make([][]string, len(rows)-1)
+	for i := range grid {
+		grid[i] = make([]string, len(cols)-1)
+	}
+	for _, c := range cells {
+		r0, r1 := sort.SearchInts(rows, c.top), sort.SearchInts(rows, c.bottom)
+		c0, c1 := sort.SearchInts(cols, c.left), sort.SearchInts(cols, c.right)
+		for r := r0; r < r1; r++ {
+			for col := c0; col < c1; col++ {
+				if (r == r0 && col == c0) || fill == FillRepeat {
+					grid[r][col] = c.text
+				}
+			}
+		}
+	}
+	t := Table{Parsed: Parsed{}}
+	for r, row := range grid {
+		if g.headerLine >= 0 && rows[r+1] <= g.headerLine {
+			t.columns = joinColumns(t.columns, row)
+			continue
+		}
+		t.Parsed = append(t.Parsed, parsedLine{
+			original: strings.Join(lines[rows[r]+1:rows[r+1]], "\n"),
+			parsed:   row,
+		})
+	}
+	return t
+}
+
+func sortedKeys(m map[int]bool) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// joinColumns appends cells of another header row to column names
+func joinColumns(columns, row []string) []string {
+	if columns == nil {
+		return append([]string{}, row...)
+	}
+	for i, cell := range row {
+		columns[i] = join(columns[i], cell)
+	}
+	return columns
+}
+
+// ParseRSTSimple parses the first reStructuredText simple table found in lines:
+//
+//	=====  =====  ======
+//	   Inputs     Output
+//	------------  ------
+//	  A      B    A or B
+//	=====  =====  ======
+//	False  False  False
+//	True          True
+//	       text   of B
+//	=====  =====  ======
+//
+// Columns are given by the ==== border, text of the last column can exceed it. A line
+// with empty first column continues cells of the row above, a ---- underline makes the
+// row above span columns (slots covered by the span are filled according to fill). Rows
+// above the second border are joined to column names.
+func ParseRSTSimple(lines []string, fill ...SpanFill) (Table, error) {
+	start := -1
+	for i, line := range lines {
+		if rstSimpleBorder.MatchString(line) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return Table{}, errors.New("can't find simple table")
+	}
+	borders := []int{start}
+	for i := start + 1; i < len(lines); i++ {
+		if !rstSimpleBorder.MatchString(lines[i]) {
+			continue
+		}
+		borders = append(borders, i)
+		if i+1 == len(lines) || isWhiteSpace(lines[i+1]) {
+			break
+		}
+	}
+	if len(borders) < 2 {
+		return Table{}, errors.New("can't find the bottom border of simple table")
+	}
+	cols := dashRuns(lines[start], '=')
+	headerEnd := -1
+	if len(borders) > 2 {
+		headerEnd = borders[1]
+	}
+
+	var header, body Parsed
+	for i := start + 1; i < borders[len(borders)-1]; i++ {
+		line := lines[i]
+		section := &body
+		if i < headerEnd {
+			section = &header
+		}
+		switch {
+		case isWhiteSpace(line) || rstSimpleBorder.MatchString(line):
+		case rstSimpleUnderline.MatchString(line) && len(*section) > 0:
+			last := &(*section)[len(*section)-1]
+			last.parsed = spanColumns(last.original, cols, dashRuns(line, '-'), spanFillOf(fill))
+		default:
+			*section = append(*section, parsedLine{
+				original: line, parsed: splitRSTSimpleRow(line, cols)})
+		}
+	}
+	t := Table{Parsed: body.JoinContinuations(KeyColumnEmpty(0))}
+	for _, row := range header.JoinContinuations(KeyColumnEmpty(0)).Lines() {
+		t.columns = joinColumns(t.columns, row)
+	}
+	return t, nil
+}
+
+// dashRuns returns extents of runs of the character
+func dashRuns(line string, dash rune) []column {
+	var result []column
+	for i, r := range []rune(line) {
+		switch {
+		case r != dash:
+		case len(result) > 0 && result[len(result)-1].to == i:
+			result[len(result)-1].to = i + 1
+		default:
+			result = append(result, column{from: i, to: i + 1})
+		}
+	}
+	return result
+}
+
+// splitRSTSimpleRow cuts the line at the start of each column, the last column takes the
+// rest of the line
+func splitRSTSimpleRow(line string, cols []column) []string {
+	dl := newDisplayLine(line)
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		from, to := c.from, dl.width()
+		if i+1 < len(cols) {
+			to = cols[i+1].from
+		}
+		if from >= dl.width() {
+			break
+		}
+		if to > dl.width() {
+			to = dl.width()
+		}
+		row[i] = strings.TrimSpace(dl.slice(from, to))
+	}
+	return row
+}
+
+// spanColumns splits the line by spans, text of a span is placed to the first column it
+// covers
+func spanColumns(line string, cols, spans []column, fill SpanFill) []string {
+	starts := make([]column, len(spans))
+	for i, s := range spans {
+		starts[i] = column{from: s.from}
+	}
+	texts := splitRSTSimpleRow(line, starts)
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		for j, s := range spans {
+			if !s.contains(c.from, c.to) {
+				continue
+			}
+			if s.from == c.from || fill == FillRepeat {
+				row[i] = texts[j]
+			}
+		}
+	}
+	return row
+}
@@ -0,0 +1,107 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type rstSuite struct{ suite.Suite }
+
+func TestRST(t *testing.T) { suite.Run(t, new(rstSuite)) }
+
+func (s *rstSuite) TestParseRSTGrid() {
+	input := `
+Grid table:
+
++------------+------------+-----------+
+| Header 1   | Header 2   | Header 3  |
++============+============+===========+
+| body row 1 | column 2   | column 3  |
++------------+------------+-----------+
+| body row 2 | Cells may span columns.|
++------------+------------+-----------+
+| body row 3 | Cells may  | - Cells   |
++------------+ span rows. | - contain |
+| body row 4 |            | - blocks. |
++------------+------------+-----------+
+
+Text after.`
+	t, err := ParseRSTGrid(strings.Split(input, "\n"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Header 1", "Header 2", "Header 3"}, t.Columns())
+	require.Equal(s.T(), [][]string{
+		{"body row 1", "column 2", "column 3"},
+		{"body row 2", "Cells may span columns.", ""},
+		{"body row 3", "Cells may span rows.", "- Cells - contain - blocks."},
+		{"body row 4", "", ""},
+	}, t.Lines())
+	require.Equal(s.T(), "| body row 2 | Cells may span columns.|", t.Parsed[1].original)
+
+	repeated, err := ParseRSTGrid(strings.Split(input, "\n"), FillRepeat)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"body row 4", "Cells may span rows.", "- Cells - contain - blocks."},
+		repeated.Lines()[3])
+}
+
+func (s *rstSuite) TestParseRSTGridWithWideRunes() {
+	input := `
++------+------+
+| 名前 | b    |
++======+======+
+| 東京 | 1    |
++------+------+`
+	t, err := ParseRSTGrid(strings.Split(input, "\n"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"名前", "b"}, t.Columns())
+	require.Equal(s.T(), [][]string{{"東京", "1"}}, t.Lines())
+}
+
+func (s *rstSuite) TestParseRSTGridWithoutTable() {
+	_, err := ParseRSTGrid([]string{"text"})
+	require.NotNil(s.T(), err)
+}
+
+func (s *rstSuite) TestParseRSTSimple() {
+	input := `
+Simple table:
+
+=====  =====  ======
+   Inputs     Output
+------------  ------
+  A      B    A or B
+=====  =====  ======
+False  False  False
+True   False  True
+       of B   continued
+False  True   True exceeding
+              the column
+
+True   True   True
+=====  =====  ======
+
+Text after.`
+	t, err := ParseRSTSimple(strings.Split(input, "\n"), FillRepeat)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Inputs A", "Inputs B", "Output A or B"}, t.Columns())
+	require.Equal(s.T(), [][]string{
+		{"False", "False", "False"},
+		{"True", "False of B", "True continued"},
+		{"False", "True", "True exceeding the column"},
+		{"True", "True", "True"},
+	}, t.Lines())
+}
+
+func (s *rstSuite) TestParseRSTSimpleWithoutHeader() {
+	input := `
+=====  =====
+a      1
+b      2
+=====  =====`
+	t, err := ParseRSTSimple(strings.Split(input, "\n"))
+	require.Nil(s.T(), err)
+	require.Nil(s.T(), t.Columns())
+	require.Equal(s.T(), [][]string{{"a", "1"}, {"b", "2"}}, t.Lines())
+}
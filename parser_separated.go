@@ -15,12 +15,37 @@ func ParseSeparated(lines []string, nbColumn int) (Parsed, error) {
 		if len(splitted) > nbColumn {
 			return result, errors.Errorf(
 				"can't parse table: too many columns: expected %d, got %d",
-				len(splitted), nbColumn)
+				nbColumn, len(splitted))
 		}
-		for len(splitted) <= nbColumn {
+		for len(splitted) < nbColumn {
 			splitted = append(splitted, "")
 		}
 		result[i] = parsedLine{parsed: splitted, original: line}
 	}
 	return result, nil
 }
+
+// ParseSeparatedAuto parses table like ParseSeparated, the number of columns is the
+// greatest number of fields in a line. It's returned together with its confidence, the
+// share of non-empty lines having all the fields.
+func ParseSeparatedAuto(lines []string) (Parsed, Layout, error) {
+	var layout Layout
+	counts := map[int]int{}
+	nonEmpty := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		n := len(Fields(line))
+		nonEmpty++
+		counts[n]++
+		layout.NbColumn = maxInt(layout.NbColumn, n)
+	}
+	if nonEmpty == 0 {
+		return nil, layout, errors.New("can't infer columns from empty input")
+	}
+	layout.Confidence = float64(counts[layout.NbColumn]) / float64(nonEmpty)
+	result, err := ParseSeparated(lines, layout.NbColumn)
+	return result, layout, err
+}
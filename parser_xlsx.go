@@ -0,0 +1,439 @@
+package table
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/firfircelik/table/convert"
+	"github.com/pkg/errors"
+)
+
+// Workbook is a XLSX (SpreadsheetML) workbook
+type Workbook struct {
+	files         map[string]*zip.File
+	sheets        []workbookSheet
+	sharedStrings []string
+	// styles maps indexes of cell styles to formats of their numbers
+	styles   map[int]numberFormat
+	date1904 bool
+}
+
+// numberFormat is a kind of number format of a cell style
+type numberFormat int
+
+const (
+	plainFormat numberFormat = iota
+	dateFormat
+	// elapsedFormat counts time in hours, minutes or seconds like [h]:mm
+	elapsedFormat
+)
+
+type workbookSheet struct {
+	name, path string
+}
+
+// OpenXLSX reads workbook from the file
+func OpenXLSX(filename string) (*Workbook, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read xlsx")
+	}
+	return ReadXLSX(bytes.NewReader(b), int64(len(b)))
+}
+
+// ReadXLSX reads workbook from the reader
+func ReadXLSX(r io.ReaderAt, size int64) (*Workbook, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't open xlsx")
+	}
+	w := &Workbook{files: map[string]*zip.File{}, styles: map[int]numberFormat{}}
+	for _, f := range z.File {
+		w.files[f.Name] = f
+	}
+	if err := w.readSheets(); err != nil {
+		return nil, err
+	}
+	if err := w.readSharedStrings(); err != nil {
+		return nil, err
+	}
+	if err := w.readStyles(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Sheets returns names of the sheets in the workbook order
+func (w *Workbook) Sheets() []string {
+	names := make([]string, len(w.sheets))
+	for i, s := range w.sheets {
+		names[i] = s.name
+	}
+	return names
+}
+
+// Sheet returns used range of the sheet (the smallest rectangle containing all non
+// empty cells). Shared and inline strings are resolved, numbers formatted as dates are
+// returned as 2006-01-02, 2006-01-02 15:04:05 or 15:04:05, elapsed time ([h]:mm) as total
+// hours, minutes and seconds like 25:30:00, booleans as TRUE or FALSE and other numbers as
+// stored. Slots of merged cells are filled according to fill (FillEmpty
+// by default).
+func (w *Workbook) Sheet(name string, fill ...SpanFill) (Parsed, error) {
+	for _, s := range w.sheets {
+		if s.name == name {
+			return w.readSheet(s.path, spanFillOf(fill))
+		}
+	}
+	return nil, errors.Errorf("can't find sheet %q", name)
+}
+
+func (w *Workbook) readXML(name string, v interface{}) error {
+	f, ok := w.files[name]
+	if !ok {
+		return errors.Errorf("can't find %s in xlsx", name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return errors.Wrapf(err, "can't open %s", name)
+	}
+	defer r.Close() // nolint: errcheck
+	return errors.Wrapf(xml.NewDecoder(r).Decode(v), "can't parse %s", name)
+}
+
+func (w *Workbook) readSheets() error {
+	var workbook struct {
+		Properties struct {
+			Date1904 string `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := w.readXML("xl/workbook.xml", &workbook); err != nil {
+		return err
+	}
+	w.date1904 = workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true"
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := w.readXML("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return err
+	}
+	targets := map[string]string{}
+	for _, r := range rels.Relationships {
+		if strings.HasPrefix(r.Target, "/") {
+			targets[r.ID] = strings.TrimPrefix(r.Target, "/")
+		} else {
+			targets[r.ID] = path.Join("xl", r.Target)
+		}
+	}
+	for _, s := range workbook.Sheets {
+		w.sheets = append(w.sheets, workbookSheet{name: s.Name, path: targets[s.ID]})
+	}
+	return nil
+}
+
+func (w *Workbook) readSharedStrings() error {
+	if _, ok := w.files["xl/sharedStrings.xml"]; !ok {
+		return nil
+	}
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := w.readXML("xl/sharedStrings.xml", &sst); err != nil {
+		return err
+	}
+	for _, si := range sst.Items {
+		w.sharedStrings = append(w.sharedStrings, si.String())
+	}
+	return nil
+}
+
+// richText is either plain text or a list of runs (phonetic runs are ignored)
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t richText) String() string {
+	s := t.T
+	for _, r := range t.Runs {
+		s += r.T
+	}
+	return s
+}
+
+func (w *Workbook) readStyles() error {
+	if _, ok := w.files["xl/styles.xml"]; !ok {
+		return nil
+	}
+	var styles struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		CellXfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if err := w.readXML("xl/styles.xml", &styles); err != nil {
+		return err
+	}
+	formats := map[int]numberFormat{}
+	for _, f := range styles.NumFmts {
+		formats[f.ID] = numberFormatOf(f.Code)
+	}
+	for i, xf := range styles.CellXfs {
+		format, custom := formats[xf.NumFmtID]
+		if !custom {
+			format = builtinNumberFormat(xf.NumFmtID)
+		}
+		w.styles[i] = format
+	}
+	return nil
+}
+
+// builtinNumberFormat returns format of ids predefined by SpreadsheetML, 46 is [h]:mm:ss
+func builtinNumberFormat(id int) numberFormat {
+	switch {
+	case id == 46:
+		return elapsedFormat
+	case (id >= 14 && id <= 22) || (id >= 27 && id <= 36) || id == 45 || id == 47 ||
+		(id >= 50 && id <= 58):
+		return dateFormat
+	}
+	return plainFormat
+}
+
+// numberFormatOf checks whether the format code contains elapsed time sections or date
+// and time parts outside of quoted text, escaped characters and [colour] or [condition]
+// sections
+func numberFormatOf(code string) numberFormat {
+	code = strings.ToLower(code)
+	inQuotes, inBrackets := false, false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case inQuotes:
+			inQuotes = c != '"'
+		case inBrackets:
+			inBrackets = c != ']'
+		case c == '"':
+			inQuotes = true
+		case c == '[':
+			// elapsed time [h], [mm] and [ss] is a time format
+			for _, elapsed := range []string{"[h]", "[hh]", "[m]", "[mm]", "[s]", "[ss]"} {
+				if strings.HasPrefix(code[i:], elapsed) {
+					return elapsedFormat
+				}
+			}
+			inBrackets = true
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		case strings.IndexByte("ymdhs", c) >= 0 && !strings.HasPrefix(code, "general"):
+			return dateFormat
+		case strings.IndexByte("ymdhs", c) >= 0:
+			return plainFormat
+		}
+	}
+	return plainFormat
+}
+
+type xlsxCell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Style  int      `xml:"s,attr"`
+	Value  string   `xml:"v"`
+	Inline richText `xml:"is"`
+}
+
+func (w *Workbook) readSheet(name string, fill SpanFill) (Parsed, error) {
+	var sheet struct {
+		Rows []struct {
+			Ref   int        `xml:"r,attr"`
+			Cells []xlsxCell `xml:"c"`
+		} `xml:"sheetData>row"`
+		MergeCells []struct {
+			Ref string `xml:"ref,attr"`
+		} `xml:"mergeCells>mergeCell"`
+	}
+	if err := w.readXML(name, &sheet); err != nil {
+		return nil, err
+	}
+	grid := newSheetGrid()
+	r := -1
+	for _, row := range sheet.Rows {
+		// references are optional, without them rows and cells follow each other
+		r++
+		if row.Ref > 0 {
+			r = row.Ref - 1
+		}
+		col := -1
+		for _, c := range row.Cells {
+			col++
+			if c.Ref != "" {
+				var err error
+				if r, col, err = parseCellRef(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			value, err := w.cellValue(c)
+			if err != nil {
+				return nil, errors.Wrapf(err, "can't read cell %s", c.Ref)
+			}
+			grid.set(r, col, value)
+		}
+	}
+	for _, m := range sheet.MergeCells {
+		refs := strings.Split(m.Ref, ":")
+		r0, c0, err := parseCellRef(refs[0])
+		if err != nil || len(refs) != 2 {
+			return nil, errors.Errorf("can't parse merged cell %q", m.Ref)
+		}
+		r1, c1, err := parseCellRef(refs[1])
+		if err != nil {
+			return nil, errors.Errorf("can't parse merged cell %q", m.Ref)
+		}
+		grid.merge(r0, c0, r1, c1, fill)
+	}
+	return grid.parsed(), nil
+}
+
+// sheetGrid collects non empty cells of a spreadsheet and keeps track of the used range
+type sheetGrid struct {
+	values                         map[[2]int]string
+	minRow, minCol, maxRow, maxCol int
+}
+
+func newSheetGrid() *sheetGrid {
+	return &sheetGrid{
+		values: map[[2]int]string{},
+		minRow: math.MaxInt32, minCol: math.MaxInt32, maxRow: -1, maxCol: -1,
+	}
+}
+
+func (g *sheetGrid) include(row, col int) {
+	g.minRow, g.minCol = minInt(g.minRow, row), minInt(g.minCol, col)
+	g.maxRow, g.maxCol = maxInt(g.maxRow, row), maxInt(g.maxCol, col)
+}
+
+// set value of the cell, empty values are not stored
+func (g *sheetGrid) set(row, col int, value string) {
+	if value == "" {
+		return
+	}
+	g.values[[2]int{row, col}] = value
+	g.include(row, col)
+}
+
+// merge cells from r0, c0 to r1, c1 (inclusive), the value of the top left cell is
+// kept and other slots are filled according to fill. Merging empty cells is ignored.
+func (g *sheetGrid) merge(r0, c0, r1, c1 int, fill SpanFill) {
+	value, ok := g.values[[2]int{r0, c0}]
+	if !ok {
+		return
+	}
+	g.include(r1, c1)
+	for r := r0; r <= r1; r++ {
+		for c := c0; c <= c1; c++ {
+			if r == r0 && c == c0 {
+				continue
+			}
+			if fill == FillRepeat {
+				g.values[[2]int{r, c}] = value
+			} else {
+				delete(g.values, [2]int{r, c})
+			}
+		}
+	}
+}
+
+// parsed returns the used range, the smallest rectangle containing all non empty cells
+func (g *sheetGrid) parsed() Parsed {
+	var rows [][]string
+	for r := g.minRow; r <= g.maxRow; r++ {
+		row := make([]string, g.maxCol-g.minCol+1)
+		for c := g.minCol; c <= g.maxCol; c++ {
+			row[c-g.minCol] = g.values[[2]int{r, c}]
+		}
+		rows = append(rows, row)
+	}
+	return FromStrStrSlice(rows)
+}
+
+func (w *Workbook) cellValue(c xlsxCell) (string, error) {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(w.sharedStrings) {
+			return "", errors.Errorf("invalid shared string %q", c.Value)
+		}
+		return w.sharedStrings[i], nil
+	case "inlineStr":
+		return c.Inline.String(), nil
+	case "b":
+		if c.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case "", "n":
+		format := w.styles[c.Style]
+		if c.Value == "" || format == plainFormat {
+			return c.Value, nil
+		}
+		serial, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid date %q", c.Value)
+		}
+		if format == elapsedFormat {
+			return formatSeconds(serial * 24 * 60 * 60), nil
+		}
+		return formatSerialDate(serial, w.date1904), nil
+	}
+	// str (formula result), e (error) and d (ISO 8601 date) are kept as stored
+	return c.Value, nil
+}
+
+// SerialToTime converts spreadsheet serial date number to time, see convert.SerialToTime
+func SerialToTime(serial float64, date1904 bool) time.Time {
+	return convert.SerialToTime(serial, date1904)
+}
+
+func formatSerialDate(serial float64, date1904 bool) string {
+	t := SerialToTime(serial, date1904)
+	switch {
+	case serial < 1:
+		return t.Format("15:04:05")
+	case serial == math.Floor(serial):
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// parseCellRef converts A1 reference to zero based row and column
+func parseCellRef(ref string) (int, int, error) {
+	ref = strings.Replace(ref, "$", "", -1)
+	col, i := 0, 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		col = col*26 + int(ref[i]-'A') + 1
+	}
+	row, err := strconv.Atoi(ref[i:])
+	if i == 0 || err != nil || row < 1 {
+		return 0, 0, errors.Errorf("invalid cell reference %q", ref)
+	}
+	return row - 1, col - 1, nil
+}
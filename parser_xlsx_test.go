@@ -0,0 +1,148 @@
+package table
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type xlsxSuite struct{ suite.Suite }
+
+func TestXLSX(t *testing.T) { suite.Run(t, new(xlsxSuite)) }
+
+// zipFiles creates zip archive containing given files
+func zipFiles(files map[string]string) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			panic(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+	return b.Bytes()
+}
+
+var statementXLSX = zipFiles(map[string]string{
+	"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+    xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets>
+    <sheet name="Summary" sheetId="1" r:id="rId2"/>
+    <sheet name="Statement" sheetId="2" r:id="rId1"/>
+  </sheets>
+</workbook>`,
+	"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="worksheet" Target="worksheets/sheet1.xml"/>
+  <Relationship Id="rId2" Type="worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+	"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>Date</t></si>
+  <si><t>Amount</t></si>
+  <si><r><t>Coffee </t></r><r><rPr><b/></rPr><t>shop</t></r><rPh><t>ignored</t></rPh></si>
+  <si><t>Statement 2018</t></si>
+</sst>`,
+	"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <numFmts count="1"><numFmt numFmtId="164" formatCode="dd\.mm\.yyyy"/></numFmts>
+  <cellXfs count="4">
+    <xf numFmtId="0"/>
+    <xf numFmtId="164"/>
+    <xf numFmtId="2"/>
+    <xf numFmtId="46"/>
+  </cellXfs>
+</styleSheet>`,
+	"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row r="2"><c r="B2" t="s"><v>3</v></c></row>
+    <row r="3"><c r="B3" t="s"><v>0</v></c><c r="C3" t="s"><v>1</v></c>
+      <c r="D3" t="inlineStr"><is><t>Note</t></is></c></row>
+    <row r="4"><c r="B4" s="1"><v>43132</v></c><c r="C4" s="2"><v>4.5</v></c>
+      <c r="D4" t="s"><v>2</v></c></row>
+    <row r="5"><c r="B5" s="1"><v>43133.5</v></c><c r="C5"><v>-12</v></c>
+      <c r="D5" t="b"><v>1</v></c></row>
+  </sheetData>
+  <mergeCells count="1"><mergeCell ref="B2:D2"/></mergeCells>
+</worksheet>`,
+	"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData><row><c><v>1</v></c><c><v>2</v></c><c s="3"><v>1.0625</v></c></row></sheetData>
+</worksheet>`,
+})
+
+func (s *xlsxSuite) TestReadXLSX() {
+	w, err := ReadXLSX(bytes.NewReader(statementXLSX), int64(len(statementXLSX)))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Summary", "Statement"}, w.Sheets())
+
+	statement, err := w.Sheet("Statement", FillRepeat)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{
+		{"Statement 2018", "Statement 2018", "Statement 2018"},
+		{"Date", "Amount", "Note"},
+		{"2018-02-01", "4.5", "Coffee shop"},
+		{"2018-02-02 12:00:00", "-12", "TRUE"},
+	}, statement.Lines())
+
+	statement, err = w.Sheet("Statement")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Statement 2018", "", ""}, statement.Lines()[0])
+	require.Equal(s.T(), []string{"2018-02-01", "4.5", "Coffee shop"},
+		statement.FindLine(LineContaining("Coffee")))
+
+	summary, err := w.Sheet("Summary")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{{"1", "2", "25:30:00"}}, summary.Lines())
+
+	_, err = w.Sheet("Missing")
+	require.NotNil(s.T(), err)
+}
+
+func (s *xlsxSuite) TestSerialToTime() {
+	require.Equal(s.T(), time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), SerialToTime(1, false))
+	require.Equal(s.T(), time.Date(1900, 2, 28, 0, 0, 0, 0, time.UTC), SerialToTime(59, false))
+	require.Equal(s.T(), time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC), SerialToTime(61, false))
+	require.Equal(s.T(), time.Date(2018, 2, 1, 6, 0, 0, 0, time.UTC), SerialToTime(43132.25, false))
+	require.Equal(s.T(), time.Date(2016, 2, 2, 0, 0, 0, 0, time.UTC), SerialToTime(40940, true))
+}
+
+func (s *xlsxSuite) TestNumberFormatOf() {
+	for code, format := range map[string]numberFormat{
+		"dd/mm/yyyy":                 dateFormat,
+		"[h]:mm":                     elapsedFormat,
+		"[ss].00":                    elapsedFormat,
+		`[$-409]d\-mmm;@`:            dateFormat,
+		`#,##0.00 "days"`:            plainFormat,
+		"[Red]0.00":                  plainFormat,
+		"[Magenta]0.00":              plainFormat,
+		"[Magenta]#,##0;[Red]-#,##0": plainFormat,
+		"General":                    plainFormat,
+	} {
+		require.Equal(s.T(), format, numberFormatOf(code), code)
+	}
+	require.Equal(s.T(), elapsedFormat, builtinNumberFormat(46))
+	require.Equal(s.T(), dateFormat, builtinNumberFormat(14))
+	require.Equal(s.T(), plainFormat, builtinNumberFormat(4))
+}
+
+func (s *xlsxSuite) TestFormatSerialDate() {
+	require.Equal(s.T(), "12:00:00", formatSerialDate(0.5, false))
+	require.Equal(s.T(), "12:00:00", formatSerialDate(0.5, true))
+	require.Equal(s.T(), "2016-02-02", formatSerialDate(40940, true))
+	require.Equal(s.T(), "2018-02-01 06:00:00", formatSerialDate(43132.25, false))
+	require.Equal(s.T(), "25:30:00", formatSeconds(1.0625*24*60*60))
+	require.Equal(s.T(), "-01:00:00", formatSeconds(-3600))
+}
@@ -0,0 +1,246 @@
+package table
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/firfircelik/table/convert"
+	"github.com/pkg/errors"
+)
+
+// Relational operations work on tables, use Parsed.WithHeader to name columns of a
+// Parsed. Rows keep their original lines, so FindLine works on the results.
+
+// Filter returns rows matching the predicate
+func (t Table) Filter(predicate func(Row) bool) Table {
+	result := Table{Parsed: Parsed{}, columns: t.columns}
+	for i, line := range t.Parsed {
+		if predicate(t.row(i)) {
+			result.Parsed = append(result.Parsed, line)
+		}
+	}
+	return result
+}
+
+// Select returns table with given columns in the given order
+func (t Table) Select(columns ...string) (Table, error) {
+	indexes := make([]int, len(columns))
+	names := make([]string, len(columns))
+	for i, name := range columns {
+		index, err := t.ColumnIndex(name)
+		if err != nil {
+			return Table{Parsed: Parsed{}}, err
+		}
+		indexes[i], names[i] = index, t.columns[index]
+	}
+	result := Table{Parsed: Parsed{}, columns: names}
+	for _, line := range t.Parsed {
+		parsed := make([]string, len(indexes))
+		for i, index := range indexes {
+			if index < len(line.parsed) {
+				parsed[i] = line.parsed[index]
+			}
+		}
+		result.Parsed = append(result.Parsed, parsedLine{original: line.original, parsed: parsed})
+	}
+	return result, nil
+}
+
+// Less compares two cells
+type Less func(a, b string) bool
+
+// StringLess compares cells as strings
+func StringLess(a, b string) bool { return a < b }
+
+// NumberLess compares cells as amounts written in the locale. Cells which are not
+// numbers follow numbers and are compared as strings.
+func NumberLess(locale convert.Locale) Less {
+	return func(a, b string) bool {
+		x, errX := convert.ParseAmount(a, locale)
+		y, errY := convert.ParseAmount(b, locale)
+		return parsedLess(errX == nil, errY == nil, a < b, func() bool {
+			return x.Value.Cmp(y.Value) < 0
+		})
+	}
+}
+
+// DateLess compares cells as dates parsed by the parser. Cells which are not dates
+// follow dates and are compared as strings.
+func DateLess(parser convert.DateParser) Less {
+	return func(a, b string) bool {
+		x, errX := parser.Parse(a)
+		y, errY := parser.Parse(b)
+		return parsedLess(errX == nil, errY == nil, a < b, func() bool { return x.Before(y) })
+	}
+}
+
+func parsedLess(okA, okB, stringLess bool, less func() bool) bool {
+	switch {
+	case okA && okB:
+		return less()
+	case okA != okB:
+		return okA
+	}
+	return stringLess
+}
+
+// SortBy returns rows sorted by the column, rows with equal cells keep their order
+func (t Table) SortBy(column string, less Less) (Table, error) {
+	index, err := t.ColumnIndex(column)
+	if err != nil {
+		return Table{Parsed: Parsed{}}, err
+	}
+	result := Table{Parsed: append(Parsed{}, t.Parsed...), columns: t.columns}
+	cell := func(i int) string {
+		if index < len(result.Parsed[i].parsed) {
+			return result.Parsed[i].parsed[index]
+		}
+		return ""
+	}
+	sort.SliceStable(result.Parsed, func(i, j int) bool { return less(cell(i), cell(j)) })
+	return result, nil
+}
+
+// Group is a set of rows having the same key
+type Group struct {
+	Key string
+	Table
+	// rows are indexes of the rows in the grouped table
+	rows []int
+}
+
+// Grouping contains groups of rows in the order of their first appearance
+type Grouping struct {
+	column string
+	groups []Group
+}
+
+// GroupBy groups rows by the cell of the column
+func (t Table) GroupBy(column string) (Grouping, error) {
+	index, err := t.ColumnIndex(column)
+	if err != nil {
+		return Grouping{}, err
+	}
+	g := Grouping{column: t.columns[index]}
+	positions := map[string]int{}
+	for i, line := range t.Parsed {
+		key, _ := t.row(i).Get(column)
+		position, ok := positions[key]
+		if !ok {
+			position = len(g.groups)
+			positions[key] = position
+			g.groups = append(g.groups, Group{
+				Key: key, Table: Table{Parsed: Parsed{}, columns: t.columns}})
+		}
+		g.groups[position].Parsed = append(g.groups[position].Parsed, line)
+		g.groups[position].rows = append(g.groups[position].rows, i)
+	}
+	return g, nil
+}
+
+// Groups returns groups in the order of their first appearance
+func (g Grouping) Groups() []Group { return g.groups }
+
+// Aggregation computes a value from rows of a group
+type Aggregation struct {
+	// Name is the name of the column with the result
+	Name      string
+	aggregate func(Group) (string, error)
+}
+
+// Aggregate returns table with a row per group. The first column is the key, the others
+// are results of the aggregations. Original of a row contains originals of the rows in
+// the group separated by new lines.
+func (g Grouping) Aggregate(aggregations ...Aggregation) (Table, error) {
+	columns := []string{g.column}
+	for _, a := range aggregations {
+		columns = append(columns, a.Name)
+	}
+	result := Table{Parsed: Parsed{}, columns: columns}
+	for _, group := range g.groups {
+		parsed := []string{group.Key}
+		originals := make([]string, len(group.Parsed))
+		for i, line := range group.Parsed {
+			originals[i] = line.original
+		}
+		for _, a := range aggregations {
+			value, err := a.aggregate(group)
+			if err != nil {
+				return Table{Parsed: Parsed{}}, errors.Wrapf(
+					err, "can't compute %s of group %q", a.Name, group.Key)
+			}
+			parsed = append(parsed, value)
+		}
+		result.Parsed = append(result.Parsed, parsedLine{
+			original: strings.Join(originals, "\n"),
+			parsed:   parsed,
+		})
+	}
+	return result, nil
+}
+
+// Count counts rows of a group
+func Count() Aggregation {
+	return Aggregation{Name: "count", aggregate: func(g Group) (string, error) {
+		return strconv.Itoa(len(g.Parsed)), nil
+	}}
+}
+
+// Sum adds amounts of the column written in the locale, empty cells are skipped. The sum
+// is exact and keeps the currency of the amounts (like -5.50 EUR), it's an error when the
+// amounts have different currencies. Rows in errors are numbered as in the grouped table.
+func Sum(column string, locale convert.Locale) Aggregation {
+	return Aggregation{Name: "sum(" + column + ")", aggregate: func(g Group) (string, error) {
+		cells, err := g.Column(column)
+		if err != nil {
+			return "", err
+		}
+		var sum convert.Decimal
+		var currency string
+		for i, cell := range cells {
+			if isWhiteSpace(cell) {
+				continue
+			}
+			a, err := convert.ParseAmount(cell, locale)
+			if err != nil {
+				return "", errors.Wrapf(err, "row %d", g.rows[i])
+			}
+			if a.Currency != "" && currency != "" && a.Currency != currency {
+				return "", errors.Errorf(
+					"row %d: can't add %s to %s", g.rows[i], a.Currency, currency)
+			}
+			if a.Currency != "" {
+				currency = a.Currency
+			}
+			sum = sum.Add(a.Value)
+		}
+		return convert.Amount{Value: sum, Currency: currency}.String(), nil
+	}}
+}
+
+// Min returns the smallest non empty cell of the column
+func Min(column string, less Less) Aggregation {
+	return extreme("min("+column+")", column, less)
+}
+
+// Max returns the greatest non empty cell of the column
+func Max(column string, less Less) Aggregation {
+	return extreme("max("+column+")", column, func(a, b string) bool { return less(b, a) })
+}
+
+func extreme(name, column string, less Less) Aggregation {
+	return Aggregation{Name: name, aggregate: func(g Group) (string, error) {
+		cells, err := g.Column(column)
+		if err != nil {
+			return "", err
+		}
+		var result string
+		for _, cell := range cells {
+			if !isWhiteSpace(cell) && (result == "" || less(cell, result)) {
+				result = cell
+			}
+		}
+		return result, nil
+	}}
+}
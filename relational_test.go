@@ -0,0 +1,137 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/firfircelik/table/convert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type relationalSuite struct{ suite.Suite }
+
+func TestRelational(t *testing.T) { suite.Run(t, new(relationalSuite)) }
+
+func statement() Table {
+	lines := []string{
+		"Date,Category,Amount",
+		"03.02.2018,food,4.50",
+		"01.02.2018,rent,1200.00",
+		"12.01.2018,food,-10",
+		"02.02.2018,food,",
+		"28.01.2018,fun,15.25",
+	}
+	t, err := FromStrStrSlice(splitLines(lines, ","), ",").WithHeader()
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func splitLines(lines []string, sep string) [][]string {
+	var rows [][]string
+	for _, line := range lines {
+		rows = append(rows, strings.Split(line, sep))
+	}
+	return rows
+}
+
+func (s *relationalSuite) TestFilterAndSelect() {
+	food := statement().Filter(func(r Row) bool {
+		category, _ := r.Get("Category")
+		return category == "food"
+	})
+	require.Equal(s.T(), []string{"Date", "Category", "Amount"}, food.Columns())
+	require.Len(s.T(), food.Parsed, 3)
+
+	selected, err := food.Select("amount", "Date")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Amount", "Date"}, selected.Columns())
+	require.Equal(s.T(), [][]string{
+		{"4.50", "03.02.2018"},
+		{"-10", "12.01.2018"},
+		{"", "02.02.2018"},
+	}, selected.Lines())
+	require.Equal(s.T(), []string{"-10", "12.01.2018"}, selected.FindLine(LineContaining("12.01.")))
+
+	_, err = food.Select("Date", "Balance")
+	require.NotNil(s.T(), err)
+}
+
+func (s *relationalSuite) TestSortBy() {
+	t := statement()
+	byDate, err := t.SortBy("Date", DateLess(convert.DateParser{Layouts: []string{"02.01.2006"}}))
+	require.Nil(s.T(), err)
+	dates, _ := byDate.Column("Date")
+	require.Equal(s.T(),
+		[]string{"12.01.2018", "28.01.2018", "01.02.2018", "02.02.2018", "03.02.2018"}, dates)
+	require.Equal(s.T(), "12.01.2018,food,-10", byDate.Parsed[0].original)
+
+	byAmount, err := t.SortBy("Amount", NumberLess(convert.Plain))
+	require.Nil(s.T(), err)
+	amounts, _ := byAmount.Column("Amount")
+	require.Equal(s.T(), []string{"-10", "4.50", "15.25", "1200.00", ""}, amounts)
+
+	byCategory, err := t.SortBy("Category", StringLess)
+	require.Nil(s.T(), err)
+	// equal cells keep their order
+	dates, _ = byCategory.Column("Date")
+	require.Equal(s.T(),
+		[]string{"03.02.2018", "12.01.2018", "02.02.2018", "28.01.2018", "01.02.2018"}, dates)
+
+	// source is left untouched
+	dates, _ = t.Column("Date")
+	require.Equal(s.T(), "03.02.2018", dates[0])
+	_, err = t.SortBy("Balance", StringLess)
+	require.NotNil(s.T(), err)
+}
+
+func (s *relationalSuite) TestGroupBy() {
+	g, err := statement().GroupBy("category")
+	require.Nil(s.T(), err)
+	require.Len(s.T(), g.Groups(), 3)
+	require.Equal(s.T(), "food", g.Groups()[0].Key)
+	require.Len(s.T(), g.Groups()[0].Parsed, 3)
+
+	result, err := g.Aggregate(Count(), Sum("Amount", convert.Plain),
+		Min("Amount", NumberLess(convert.Plain)), Max("Date", StringLess))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(),
+		[]string{"Category", "count", "sum(Amount)", "min(Amount)", "max(Date)"}, result.Columns())
+	require.Equal(s.T(), [][]string{
+		{"food", "3", "-5.50", "-10", "12.01.2018"},
+		{"rent", "1", "1200.00", "1200.00", "01.02.2018"},
+		{"fun", "1", "15.25", "15.25", "28.01.2018"},
+	}, result.Lines())
+	require.Equal(s.T(), "03.02.2018,food,4.50\n12.01.2018,food,-10\n02.02.2018,food,",
+		result.Parsed[0].original)
+	require.Equal(s.T(), "rent", result.FindLine(LineContaining("1200.00"))[0])
+
+	_, err = statement().GroupBy("Balance")
+	require.NotNil(s.T(), err)
+}
+
+func (s *relationalSuite) TestAggregationErrors() {
+	lines := []string{"Category;Amount", "fun;2 USD", "food;4,50 EUR", "food;1 USD", "food;x"}
+	t, err := FromStrStrSlice(splitLines(lines, ";"), ";").WithHeader()
+	require.Nil(s.T(), err)
+	g, err := t.GroupBy("Category")
+	require.Nil(s.T(), err)
+	_, err = g.Aggregate(Sum("Amount", convert.German))
+	require.NotNil(s.T(), err)
+	require.Equal(s.T(), `can't compute sum(Amount) of group "food": row 2: can't add USD to EUR`,
+		err.Error())
+	_, err = g.Aggregate(Sum("Amount", convert.English))
+	require.NotNil(s.T(), err)
+	require.Contains(s.T(), err.Error(), "row 1: ")
+
+	food := t.Filter(func(r Row) bool { return r.Cells()[1] == "4,50 EUR" })
+	g, err = food.GroupBy("Category")
+	require.Nil(s.T(), err)
+	result, err := g.Aggregate(Sum("Amount", convert.German))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{{"food", "4.50 EUR"}}, result.Lines())
+	_, err = g.Aggregate(Max("Balance", StringLess))
+	require.NotNil(s.T(), err)
+}
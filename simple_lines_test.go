@@ -100,8 +100,6 @@ func (s *tableSuite) TestFields() {
 }
 
 func (s *tableSuite) TestColumnOnRealData() {
-	// TODO: This test shows that parser does not deal well with runes which are longer than one
-	// byte. parser should be reworked to correctly with UTF.
 	lines := `
 Grain          Bids         Change (¢/bu)           Basis            Change
                           NOT ON THE RIVER
@@ -115,7 +113,7 @@ Corn       3.6775-3.7075       DN 1.5             9H to 12H           UNCH
 Soybeans   8.5175-8.5575       DN 0.75          -21H to -17H          UNCH`
 	result, err := columns(strings.Split(lines, "\n"), 5)
 	require.Nil(s.T(), err)
-	require.Equal(s.T(), []column{{0, 9}, {11, 24}, {26, 42}, {48, 60}, {70, 76}}, result)
+	require.Equal(s.T(), []column{{0, 9}, {11, 24}, {26, 42}, {48, 60}, {69, 75}}, result)
 }
 
 func (s *tableSuite) TestColumnOnSingleColumn() {
@@ -209,3 +207,261 @@ func (s *tableSuite) TestParseAlignedColumnsAreExtendedTowardsLineEnd() {
 		{"11 ", "222 ", "3333"},
 	}, result.Lines())
 }
+
+func (s *tableSuite) TestFieldsAreMeasuredInDisplayCells() {
+	testcases := []struct {
+		input  string
+		result []field
+	}{
+		{
+			input: "Zoë  b",
+			result: []field{
+				{"Zoë", 0},
+				{"b", 5}},
+		}, {
+			// e followed by combining diaeresis
+			input: "Zoe\u0308  b",
+			result: []field{
+				{"Zoe\u0308", 0},
+				{"b", 5}},
+		}, {
+			input: "東京  b",
+			result: []field{
+				{"東京", 0},
+				{"b", 6}},
+		}, {
+			input: "a\tb",
+			result: []field{
+				{"a", 0},
+				{"b", 8}},
+		},
+	}
+	for _, t := range testcases {
+		require.Equal(s.T(), t.result, extractFields(t.input))
+	}
+}
+
+func (s *tableSuite) TestParseAlignedMixedScripts() {
+	lines := []string{
+		"Merchant      Amount  Currency",
+		"Café Zoë      12,50   €",
+		"東京ストア    1200    ¥",
+		"Crème brûlée  7,00    €",
+		"Pizza 🍕      9,90    €",
+	}
+	result, err := ParseAligned(lines, 3)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{
+		{"Merchant     ", " Amount ", " Currency"},
+		{"Café Zoë     ", " 12,50  ", " €"},
+		{"東京ストア   ", " 1200   ", " ¥"},
+		{"Crème brûlée ", " 7,00   ", " €"},
+		{"Pizza 🍕     ", " 9,90   ", " €"},
+	}, result.Lines())
+}
+
+func (s *tableSuite) TestParseAlignedExpandsTabs() {
+	lines := []string{
+		"name\tamount",
+		"Zoë\t12",
+		"abcdefg  3",
+	}
+	result, err := ParseAligned(lines, 2)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{
+		{"name   ", "amount"},
+		{"Zoë    ", "12"},
+		{"abcdefg", " 3"},
+	}, result.Lines())
+}
+
+func (s *tableSuite) TestInferLayoutOnRealData() {
+	lines := `
+Grain          Bids         Change (¢/bu)           Basis            Change
+                          NOT ON THE RIVER
+SRW Wheat  4.6450-4.6550       DN 0.75            7H to 8H            UNCH
+Corn       3.6575-3.6775       DN 1.5             7H to 9H            UNCH
+Soybeans      8.5175           DN 0.75              -21H              UNCH`
+	layout, err := InferLayout(strings.Split(lines, "\n"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), 5, layout.NbColumn)
+	require.Equal(s.T(), 1.0, layout.Confidence)
+}
+
+func (s *tableSuite) TestParseAlignedAutoWhenNoLineIsComplete() {
+	lines := `
+aaa        ccc
+      bbb  ccc
+aaa   bbb`
+	result, layout, err := ParseAlignedAuto(strings.Split(lines, "\n"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), 3, layout.NbColumn)
+	require.Equal(s.T(), 1.0, layout.Confidence)
+	require.Equal(s.T(), [][]string{
+		{"", "", ""},
+		{"aaa  ", "      ", " ccc"},
+		{"     ", "  bbb ", " ccc"},
+		{"aaa  ", "  bbb", ""},
+	}, result.Lines())
+}
+
+func (s *tableSuite) TestInferLayoutReportsLowConfidence() {
+	lines := []string{
+		"a   b   c",
+		"a   b   c",
+		"a   bbbbbbb",
+	}
+	layout, err := InferLayout(lines)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), 3, layout.NbColumn)
+	require.InDelta(s.T(), 2.0/3, layout.Confidence, 1e-9)
+}
+
+func (s *tableSuite) TestInferLayoutOnEmptyInput() {
+	_, err := InferLayout([]string{"", "  "})
+	require.NotNil(s.T(), err)
+}
+
+func (s *tableSuite) TestParseSeparated() {
+	result, err := ParseSeparated([]string{"a  b  c", "d"}, 3)
+	require.Nil(s.T(), err)
+	// rows are padded to exactly nbColumn cells
+	require.Equal(s.T(), [][]string{{"a", "b", "c"}, {"d", "", ""}}, result.Lines())
+
+	_, err = ParseSeparated([]string{"a  b  c"}, 2)
+	require.EqualError(s.T(), err, "can't parse table: too many columns: expected 2, got 3")
+}
+
+func (s *tableSuite) TestParseSeparatedAuto() {
+	lines := []string{"a  b  c", "", "  d  e"}
+	result, layout, err := ParseSeparatedAuto(lines)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), 3, layout.NbColumn)
+	require.InDelta(s.T(), 0.5, layout.Confidence, 1e-9)
+	require.Equal(s.T(), [][]string{{"a", "b", "c"}, {"", "", ""}, {"d", "e", ""}}, result.Lines())
+
+	lines = []string{
+		"name  amount  currency",
+		"Coffee shop downtown  4.50  EUR",
+		"Rent  1200  EUR",
+	}
+	result, layout, err = ParseSeparatedAuto(lines)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), Layout{NbColumn: 3, Confidence: 1}, layout)
+	require.Equal(s.T(), []string{"Coffee shop downtown", "4.50", "EUR"}, result.Lines()[1])
+
+	_, _, err = ParseSeparatedAuto([]string{" "})
+	require.NotNil(s.T(), err)
+}
+
+func (s *tableSuite) TestInferLayoutWithoutFittingLayout() {
+	_, err := InferLayout([]string{
+		"name  amount  currency",
+		"Coffee shop downtown  4.50  EUR",
+		"Rent  1200  EUR",
+	})
+	require.NotNil(s.T(), err)
+}
+
+func (s *tableSuite) TestParseAlignedWithHeader() {
+	lines := `
+Statement 2018
+Date        Description         Amount    Flag
+01.02.2018  Coffee                4.50     x
+            Rent               1200.00
+03.02.2018                       12.00    yes`
+	result, err := ParseAlignedWithHeader(strings.Split(lines, "\n"), LineContaining("Date"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Date", "Description", "Amount", "Flag"}, result.Columns())
+	require.Len(s.T(), result.Lines(), 3)
+	for i, expected := range [][]string{
+		{"01.02.2018", "Coffee", "4.50", "x"},
+		{"", "Rent", "1200.00", ""},
+		{"03.02.2018", "", "12.00", "yes"},
+	} {
+		require.Equal(s.T(), expected, trimSpace(result.Lines()[i]))
+	}
+}
+
+func (s *tableSuite) TestParseAlignedWithHeaderAtWrongIndex() {
+	_, err := ParseAlignedWithHeaderAt([]string{"a  b"}, 1)
+	require.NotNil(s.T(), err)
+}
+
+func (s *tableSuite) TestParseAlignedWithHeaderNotFound() {
+	_, err := ParseAlignedWithHeader([]string{"a  b"}, LineContaining("c"))
+	require.NotNil(s.T(), err)
+}
+
+func (s *tableSuite) TestJoinContinuations() {
+	lines := `
+Date        Description              Amount
+01.02.2018  Transfer to John           10.00
+            Smith, reference
+            12345
+02.02.2018  Coffee                      4.50
+
+03.02.2018  Rent                     1200.00`
+	result, err := ParseAligned(strings.Split(lines, "\n"), 3)
+	require.Nil(s.T(), err)
+	joined := result.SkipOneLine().JoinContinuations(KeyColumnEmpty(0))
+	require.Len(s.T(), joined, 5)
+	require.Equal(s.T(), []string{"01.02.2018", "Transfer to John Smith, reference 12345", "10.00"},
+		trimSpace(joined.Lines()[1]))
+	require.Equal(s.T(), []string{"", "", ""}, joined.Lines()[3])
+	require.Equal(s.T(), []string{"01.02.2018", "Transfer to John Smith, reference 12345", "10.00"},
+		trimSpace(joined.FindLine(LineContaining("12345"))))
+	require.Equal(s.T(), "01.02.2018  Transfer to John           10.00\n"+
+		"            Smith, reference\n"+
+		"            12345", joined[1].original)
+	// source is left untouched
+	require.Equal(s.T(), "Transfer to John", strings.TrimSpace(result.Lines()[2][1]))
+}
+
+func (s *tableSuite) TestWithHeader() {
+	t, err := FromStrStrSlice([][]string{
+		{" Date ", "Description", "Amount"},
+		{"01.02.2018", "Coffee", "4.50"},
+		{"02.02.2018", "Rent"},
+	}).WithHeader()
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"Date", "Description", "Amount"}, t.Columns())
+	require.Len(s.T(), t.Parsed, 2)
+
+	amounts, err := t.Column("amount")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"4.50", ""}, amounts)
+	_, err = t.Column("Balance")
+	require.NotNil(s.T(), err)
+
+	index, err := t.ColumnIndex(" DESCRIPTION")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), 1, index)
+
+	row, err := t.Row(1)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []string{"02.02.2018", "Rent"}, row.Cells())
+	for _, i := range []int{-1, 2} {
+		_, err = t.Row(i)
+		require.NotNil(s.T(), err)
+	}
+	_, err = t.Row(2)
+	require.EqualError(s.T(), err, "can't find row 2, table has 2 rows")
+	description, err := row.Get("Description")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), "Rent", description)
+	amount, err := row.Get("Amount")
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), "", amount)
+	_, err = row.Get("Balance")
+	require.NotNil(s.T(), err)
+}
+
+func (s *tableSuite) TestWithInvalidHeader() {
+	_, err := Parsed{}.WithHeader()
+	require.NotNil(s.T(), err)
+	_, err = FromStrStrSlice([][]string{{"Date", "Amount", "date"}}).WithHeader()
+	require.Equal(s.T(), `column "date" is repeated at 0 and 2 in header`, err.Error())
+	_, err = FromStrStrSlice([][]string{{"Date", " ", "Amount"}}).WithHeader()
+	require.Equal(s.T(), `column 1 has no name in header ["Date" "" "Amount"]`, err.Error())
+}
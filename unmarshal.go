@@ -0,0 +1,394 @@
+package table
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/firfircelik/table/convert"
+	"github.com/pkg/errors"
+)
+
+// defaultTimeLayouts are tried for time.Time fields without layout tag
+var defaultTimeLayouts = []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	decimalType         = reflect.TypeOf(convert.Decimal{})
+	amountType          = reflect.TypeOf(convert.Amount{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// UnmarshalError describes a cell which can't be stored into a struct field. Row is
+// the index of the row in the unmarshalled Parsed.
+type UnmarshalError struct {
+	Row    int
+	Column string
+	Value  string
+	Err    error
+}
+
+// Error implements error
+func (e *UnmarshalError) Error() string {
+	return fmt.Sprintf("row %d, column %s: can't unmarshal %q: %v",
+		e.Row, e.Column, e.Value, e.Err)
+}
+
+// Unmarshal stores rows into v, a pointer to a slice of structs (or of pointers to
+// structs). Fields are bound to columns by tags:
+//
+//	type Payment struct {
+//		Paid   time.Time `table:"Date" layout:"02.01.2006"`
+//		Amount float64   `table:"Amount"`
+//		Note   *string   `table:"#3"`
+//	}
+//
+// A name is matched against the header case insensitively with whitespace collapsed,
+// #N binds the field to the column at index N and fields without tag are ignored. When
+// some field is bound by name the first row is the header, otherwise all rows are data.
+//
+// Cells are trimmed. Strings, integers, floats, bools, time.Time (parsed with layout
+// tag or as 2006-01-02, 2006-01-02 15:04:05 or RFC 3339) and types implementing
+// encoding.TextUnmarshaler are supported. Pointer fields are nil for empty cells, other
+// fields except strings require a value. Floats, convert.Decimal and convert.Amount
+// fields with locale tag (en, de, fr or ch) are parsed by convert.ParseAmount, so they
+// may contain thousand separators and currency:
+//
+//	Amount convert.Decimal `table:"Betrag" locale:"de"`
+//
+// When a date parser is given, time.Time fields without layout tag are parsed by its
+// ParseColumn, so several layouts, dates without year and serial numbers are accepted
+// and the day and month order is decided for the whole column.
+func Unmarshal(p Parsed, v interface{}, dates ...convert.DateParser) error {
+	binding, err := newStructBinding(v, dates)
+	if err != nil {
+		return err
+	}
+	if !binding.byName() {
+		return binding.unmarshal(p, nil, 0)
+	}
+	header, ok := p.Head()
+	if !ok {
+		return binding.unmarshal(Parsed{}, nil, 0)
+	}
+	return binding.unmarshal(p.SkipOneLine(), header, 1)
+}
+
+// UnmarshalTable stores rows of the table into v like Unmarshal, names are matched
+// against columns of the table and all its rows are data
+func UnmarshalTable(t Table, v interface{}, dates ...convert.DateParser) error {
+	binding, err := newStructBinding(v, dates)
+	if err != nil {
+		return err
+	}
+	return binding.unmarshal(t.Parsed, t.Columns(), 0)
+}
+
+// structBinding binds fields of a struct to columns
+type structBinding struct {
+	slice  reflect.Value
+	item   reflect.Type
+	fields []fieldBinding
+	dates  *convert.DateParser
+}
+
+type fieldBinding struct {
+	index  []int
+	name   string
+	column int
+	layout string
+	locale *convert.Locale
+}
+
+func (f fieldBinding) columnName() string {
+	if f.name != "" {
+		return strconv.Quote(f.name)
+	}
+	return strconv.Itoa(f.column)
+}
+
+func newStructBinding(v interface{}, dates []convert.DateParser) (*structBinding, error) {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		return nil, errors.Errorf("can't unmarshal into %T, expected pointer to slice", v)
+	}
+	b := &structBinding{slice: ptr.Elem(), item: ptr.Elem().Type().Elem()}
+	if len(dates) > 0 {
+		b.dates = &dates[0]
+	}
+	structType := b.item
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, errors.Errorf("can't unmarshal into %T, expected slice of structs", v)
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		f := structType.Field(i)
+		tag, ok := f.Tag.Lookup("table")
+		if !ok || tag == "" || tag == "-" || f.PkgPath != "" {
+			continue
+		}
+		binding := fieldBinding{
+			index: f.Index, name: tag, column: -1, layout: f.Tag.Get("layout")}
+		if len(tag) > 1 && tag[0] == '#' && allDigits(tag[1:]) {
+			binding.name = ""
+			binding.column, _ = strconv.Atoi(tag[1:])
+		}
+		if name, ok := f.Tag.Lookup("locale"); ok {
+			locale, ok := convert.LocaleByName(name)
+			if !ok {
+				return nil, errors.Errorf("field %s has unknown locale %q", f.Name, name)
+			}
+			binding.locale = &locale
+		}
+		b.fields = append(b.fields, binding)
+	}
+	return b, nil
+}
+
+func (b *structBinding) byName() bool {
+	for _, f := range b.fields {
+		if f.name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshal rows into the slice, offset is the index of the first row in the input
+func (b *structBinding) unmarshal(rows Parsed, header []string, offset int) error {
+	for i := range b.fields {
+		f := &b.fields[i]
+		if f.name == "" {
+			continue
+		}
+		var err error
+		if f.column, err = columnIndex(header, f.name); err != nil {
+			return err
+		}
+	}
+	columnDates, err := b.parseDates(rows, offset)
+	if err != nil {
+		return err
+	}
+	result := reflect.MakeSlice(b.slice.Type(), 0, len(rows))
+	for i, line := range rows {
+		item := reflect.New(b.item).Elem()
+		value := item
+		if b.item.Kind() == reflect.Ptr {
+			item.Set(reflect.New(b.item.Elem()))
+			value = item.Elem()
+		}
+		for k, f := range b.fields {
+			var cell string
+			if f.column < len(line.parsed) {
+				cell = strings.TrimSpace(line.parsed[f.column])
+			}
+			setter := func(v reflect.Value) error { return setField(v, cell, f) }
+			if dates, ok := columnDates[k]; ok {
+				setter = func(v reflect.Value) error { return setTime(v, cell, dates[i]) }
+			}
+			if err := setter(value.FieldByIndex(f.index)); err != nil {
+				return &UnmarshalError{
+					Row: offset + i, Column: f.columnName(), Value: cell, Err: err}
+			}
+		}
+		result = reflect.Append(result, item)
+	}
+	b.slice.Set(result)
+	return nil
+}
+
+// parseDates parses columns of time fields without layout with the date parser, the
+// result is indexed by fields
+func (b *structBinding) parseDates(rows Parsed, offset int) (map[int][]time.Time, error) {
+	result := map[int][]time.Time{}
+	if b.dates == nil {
+		return result, nil
+	}
+	for k, f := range b.fields {
+		t := b.item
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		t = t.FieldByIndex(f.index).Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t != timeType || f.layout != "" {
+			continue
+		}
+		cells := make([]string, len(rows))
+		for i, line := range rows {
+			if f.column < len(line.parsed) {
+				cells[i] = line.parsed[f.column]
+			}
+		}
+		dates, err := b.dates.ParseColumn(cells)
+		if dateErr, ok := err.(*convert.DateError); ok {
+			reason := "not a date"
+			if dateErr.Reason != "" {
+				reason = dateErr.Reason
+			}
+			return nil, &UnmarshalError{Row: offset + dateErr.Index, Column: f.columnName(),
+				Value: dateErr.Value, Err: errors.New(reason)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[k] = dates
+	}
+	return result, nil
+}
+
+// setTime sets already parsed time to time or pointer to time field
+func setTime(v reflect.Value, cell string, t time.Time) error {
+	switch {
+	case cell == "" && v.Kind() == reflect.Ptr:
+		v.Set(reflect.Zero(v.Type()))
+	case cell == "":
+		return errors.New("value is required")
+	case v.Kind() == reflect.Ptr:
+		v.Set(reflect.ValueOf(&t))
+	default:
+		v.Set(reflect.ValueOf(t))
+	}
+	return nil
+}
+
+// setField converts the cell to the type of the field
+func setField(v reflect.Value, cell string, f fieldBinding) error {
+	if v.Kind() == reflect.Ptr {
+		if cell == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		ptr := reflect.New(v.Type().Elem())
+		if err := setField(ptr.Elem(), cell, f); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+	if f.locale != nil && cell != "" {
+		if ok, err := setAmount(v, cell, *f.locale); ok {
+			return err
+		}
+	}
+	// time.Time is a TextUnmarshaler too, but it's parsed with the layout tag
+	if v.Type() != timeType && v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(cell))
+	}
+	if cell == "" && v.Kind() != reflect.String {
+		return errors.New("value is required")
+	}
+	switch {
+	case v.Type() == timeType:
+		t, err := parseTime(cell, f.layout)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Kind() == reflect.String:
+		v.SetString(cell)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return errors.New("not a bool")
+		}
+		v.SetBool(b)
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Errorf("not a %s", v.Type())
+		}
+		v.SetInt(n)
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uintptr:
+		n, err := strconv.ParseUint(cell, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Errorf("not a %s", v.Type())
+		}
+		v.SetUint(n)
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(cell, v.Type().Bits())
+		if err != nil {
+			return errors.Errorf("not a %s", v.Type())
+		}
+		v.SetFloat(n)
+	default:
+		return errors.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// setAmount parses amounts written in the locale, it returns false for fields of other
+// types
+func setAmount(v reflect.Value, cell string, locale convert.Locale) (bool, error) {
+	isFloat := v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+	if v.Type() != amountType && v.Type() != decimalType && !isFloat {
+		return false, nil
+	}
+	a, err := convert.ParseAmount(cell, locale)
+	if err != nil {
+		return true, errors.New("not an amount")
+	}
+	switch {
+	case v.Type() == amountType:
+		v.Set(reflect.ValueOf(a))
+	case v.Type() == decimalType:
+		v.Set(reflect.ValueOf(a.Value))
+	default:
+		v.SetFloat(a.Value.Float64())
+	}
+	return true, nil
+}
+
+// Amount parses cell of the row and column as amount written in the locale
+func (p Parsed) Amount(row, column int, locale convert.Locale) (convert.Amount, error) {
+	if row < 0 || row >= len(p) || column < 0 || column >= len(p[row].parsed) {
+		return convert.Amount{}, errors.Errorf("can't find cell at row %d, column %d", row, column)
+	}
+	a, err := convert.ParseAmount(p[row].parsed[column], locale)
+	return a, errors.Wrapf(err, "row %d, column %d", row, column)
+}
+
+// Dates parses cells of the column with the date parser, see DateParser.ParseColumn
+func (p Parsed) Dates(column int, parser convert.DateParser) ([]time.Time, error) {
+	cells := make([]string, len(p))
+	for i, line := range p {
+		if column < len(line.parsed) {
+			cells[i] = line.parsed[column]
+		}
+	}
+	dates, err := parser.ParseColumn(cells)
+	if dateErr, ok := err.(*convert.DateError); ok {
+		msg := fmt.Sprintf("can't parse date %q at row %d, column %d",
+			dateErr.Value, dateErr.Index, column)
+		if dateErr.Reason != "" {
+			msg += ": " + dateErr.Reason
+		}
+		return nil, errors.New(msg)
+	}
+	return dates, err
+}
+
+func parseTime(cell, layout string) (time.Time, error) {
+	if layout != "" {
+		t, err := time.Parse(layout, cell)
+		if err != nil {
+			return t, errors.Errorf("not a time in layout %q", layout)
+		}
+		return t, nil
+	}
+	for _, layout := range defaultTimeLayouts {
+		if t, err := time.Parse(layout, cell); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("not a time")
+}
@@ -0,0 +1,224 @@
+package table
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firfircelik/table/convert"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type unmarshalSuite struct{ suite.Suite }
+
+func TestUnmarshal(t *testing.T) { suite.Run(t, new(unmarshalSuite)) }
+
+// currency implements encoding.TextUnmarshaler
+type currency string
+
+func (c *currency) UnmarshalText(text []byte) error {
+	if len(text) != 3 || strings.ToUpper(string(text)) != string(text) {
+		return errors.New("not a currency code")
+	}
+	*c = currency(text)
+	return nil
+}
+
+type payment struct {
+	Paid     time.Time `table:"Date" layout:"02.01.2006"`
+	Amount   float64   `table:" amount "`
+	Count    int       `table:"Count"`
+	Settled  bool      `table:"Settled"`
+	Currency currency  `table:"Currency"`
+	Note     *string   `table:"#5"`
+	Fee      *uint8    `table:"Fee"`
+	ignored  string    `table:"Note"` // nolint: structcheck, unused
+	Skipped  string
+}
+
+var payments = FromStrStrSlice([][]string{
+	{"Date", "Amount", "Count", "Settled", "Currency", "Note", "Fee"},
+	{"01.02.2018", " 4.50 ", "1", "true", "EUR", "coffee", "2"},
+	{"02.02.2018", "-12", "3", "FALSE", "USD", " ", ""},
+})
+
+func (s *unmarshalSuite) TestUnmarshal() {
+	var result []payment
+	require.Nil(s.T(), Unmarshal(payments, &result))
+	coffee, fee := "coffee", uint8(2)
+	require.Equal(s.T(), []payment{
+		{Paid: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC), Amount: 4.5, Count: 1, Settled: true,
+			Currency: "EUR", Note: &coffee, Fee: &fee},
+		{Paid: time.Date(2018, 2, 2, 0, 0, 0, 0, time.UTC), Amount: -12, Count: 3,
+			Currency: "USD"},
+	}, result)
+
+	var pointers []*payment
+	require.Nil(s.T(), UnmarshalTable(Table{Parsed: payments[1:], columns: payments[0].parsed}, &pointers))
+	require.Len(s.T(), pointers, 2)
+	require.Equal(s.T(), result[1], *pointers[1])
+}
+
+func (s *unmarshalSuite) TestUnmarshalByIndex() {
+	type row struct {
+		Name  string     `table:"#0"`
+		Born  time.Time  `table:"#1"`
+		Score int64      `table:"#2"`
+		Seen  *time.Time `table:"#3"`
+	}
+	var result []row
+	require.Nil(s.T(), Unmarshal(FromStrStrSlice([][]string{
+		{"Ann", "1990-05-01", "7", "2018-02-01 10:30:00"},
+		{"Bob", "1985-01-02", "-1", ""},
+		{"Eve", "1980-03-04T05:06:07Z", "0"},
+	}), &result))
+	seen := time.Date(2018, 2, 1, 10, 30, 0, 0, time.UTC)
+	require.Equal(s.T(), []row{
+		{Name: "Ann", Born: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC), Seen: &seen, Score: 7},
+		{Name: "Bob", Born: time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC), Score: -1},
+		{Name: "Eve", Born: time.Date(1980, 3, 4, 5, 6, 7, 0, time.UTC)},
+	}, result)
+}
+
+func (s *unmarshalSuite) TestErrors() {
+	testcases := []struct {
+		row   []string
+		error string
+	}{
+		{[]string{"1.2.2018", "1", "1", "true", "EUR", "", ""},
+			`row 1, column "Date": can't unmarshal "1.2.2018": not a time in layout "02.01.2006"`},
+		{[]string{"01.02.2018", "1,5", "1", "true", "EUR", "", ""},
+			`row 1, column " amount ": can't unmarshal "1,5": not a float64`},
+		{[]string{"01.02.2018", "1", "", "true", "EUR", "", ""},
+			`row 1, column "Count": can't unmarshal "": value is required`},
+		{[]string{"01.02.2018", "1", "1", "yes", "EUR", "", ""},
+			`row 1, column "Settled": can't unmarshal "yes": not a bool`},
+		{[]string{"01.02.2018", "1", "1", "true", "eur", "", ""},
+			`row 1, column "Currency": can't unmarshal "eur": not a currency code`},
+		{[]string{"01.02.2018", "1", "1", "true", "EUR", "", "300"},
+			`row 1, column "Fee": can't unmarshal "300": not a uint8`},
+	}
+	for _, tc := range testcases {
+		var result []payment
+		err := Unmarshal(append(payments[:1:1], FromStrStrSlice([][]string{tc.row})...), &result)
+		require.NotNil(s.T(), err)
+		require.Equal(s.T(), tc.error, err.Error())
+		_, ok := err.(*UnmarshalError)
+		require.True(s.T(), ok)
+	}
+
+	var result []payment
+	err := Unmarshal(FromStrStrSlice([][]string{{"Date", "Amount"}}), &result)
+	require.NotNil(s.T(), err)
+	require.Contains(s.T(), err.Error(), `can't find column "Count"`)
+	require.NotNil(s.T(), Unmarshal(payments, result))
+	var ints []int
+	require.NotNil(s.T(), Unmarshal(payments, &ints))
+	var channels []struct {
+		C chan int `table:"#0"`
+	}
+	require.NotNil(s.T(), Unmarshal(payments, &channels))
+}
+
+func (s *unmarshalSuite) TestUnmarshalAmounts() {
+	type row struct {
+		Amount  convert.Decimal  `table:"Betrag" locale:"de"`
+		Balance *convert.Amount  `table:"Saldo" locale:"de"`
+		Fee     float64          `table:"Gebühr" locale:"de"`
+		Plain   *convert.Decimal `table:"Plain"`
+	}
+	var result []row
+	require.Nil(s.T(), Unmarshal(FromStrStrSlice([][]string{
+		{"Betrag", "Saldo", "Gebühr", "Plain"},
+		{"1.234,56-", "10.000,00 €", "(0,50)", "-1.5"},
+		{"7", "", "1,25 EUR", ""},
+	}), &result))
+	require.Equal(s.T(), []row{
+		{Amount: convert.NewDecimal(-123456, 2),
+			Balance: &convert.Amount{Value: convert.NewDecimal(1000000, 2), Currency: "EUR"},
+			Fee:     -0.5, Plain: decimalPtr(convert.NewDecimal(-15, 1))},
+		{Amount: convert.NewDecimal(7, 0), Fee: 1.25},
+	}, result)
+
+	err := Unmarshal(FromStrStrSlice([][]string{
+		{"Betrag", "Saldo", "Gebühr", "Plain"},
+		{"1,234.56", "", "0", ""},
+	}), &result)
+	require.NotNil(s.T(), err)
+	require.Equal(s.T(), `row 1, column "Betrag": can't unmarshal "1,234.56": not an amount`, err.Error())
+
+	var unknown []struct {
+		Amount float64 `table:"Betrag" locale:"xx"`
+	}
+	require.NotNil(s.T(), Unmarshal(FromStrStrSlice([][]string{{"Betrag"}}), &unknown))
+}
+
+func decimalPtr(d convert.Decimal) *convert.Decimal { return &d }
+
+func (s *unmarshalSuite) TestParsedAmount() {
+	p := FromStrStrSlice([][]string{{"Coffee", "CHF 4'500.50"}, {"Tea", "n/a"}})
+	a, err := p.Amount(0, 1, convert.Swiss)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), "4500.50 CHF", a.String())
+	_, err = p.Amount(1, 1, convert.Swiss)
+	require.NotNil(s.T(), err)
+	require.Contains(s.T(), err.Error(), "row 1, column 1")
+	_, err = p.Amount(2, 0, convert.Swiss)
+	require.NotNil(s.T(), err)
+}
+
+func (s *unmarshalSuite) TestUnmarshalDates() {
+	type row struct {
+		Booked time.Time  `table:"Booked"`
+		Value  *time.Time `table:"Value"`
+		Paid   time.Time  `table:"Paid" layout:"2006-01-02"`
+	}
+	rows := FromStrStrSlice([][]string{
+		{"Booked", "Value", "Paid"},
+		{"03/02/2018", "Dec-30", "2018-02-01"},
+		{"25/02/2018", "", "2018-02-02"},
+	})
+	parser := convert.DateParser{
+		Layouts: []string{"01/02/2006", "02/01/2006", "Jan-02"}, Reference: date(2018, 2, 28)}
+	var result []row
+	require.Nil(s.T(), Unmarshal(rows, &result, parser))
+	value := date(2017, 12, 30)
+	require.Equal(s.T(), []row{
+		{Booked: date(2018, 2, 3), Value: &value, Paid: date(2018, 2, 1)},
+		{Booked: date(2018, 2, 25), Paid: date(2018, 2, 2)},
+	}, result)
+
+	// without date parser the default layouts are used per cell
+	require.NotNil(s.T(), Unmarshal(rows, &result))
+
+	rows = append(rows, FromStrStrSlice([][]string{{"", "", "2018-02-03"}, {"31/31/2018", "", ""}})...)
+	err := Unmarshal(rows, &result, parser)
+	require.Equal(s.T(), `row 4, column "Booked": can't unmarshal "31/31/2018": not a date`, err.Error())
+	err = Unmarshal(rows[:4], &result, parser)
+	require.Equal(s.T(), `row 3, column "Booked": can't unmarshal "": value is required`, err.Error())
+
+	rows[4].parsed[0] = "02/14/2018"
+	err = Unmarshal(rows, &result, parser)
+	require.Equal(s.T(), `row 4, column "Booked": can't unmarshal "02/14/2018": `+
+		`day and month order differs from "25/02/2018"`, err.Error())
+}
+
+func (s *unmarshalSuite) TestParsedDates() {
+	p := FromStrStrSlice([][]string{{"a", "02.01.2018"}, {"b"}, {"c", "43132"}})
+	dates, err := p.Dates(1, convert.DateParser{Serial: true})
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []time.Time{date(2018, 1, 2), {}, date(2018, 2, 1)}, dates)
+	_, err = p.Dates(0, convert.DateParser{})
+	require.Equal(s.T(), `can't parse date "a" at row 0, column 0`, err.Error())
+
+	p = FromStrStrSlice([][]string{{"01/02/2018"}, {"03/04/2018"}})
+	_, err = p.Dates(0, convert.DateParser{})
+	require.Equal(s.T(), `can't parse date "01/02/2018" at row 0, column 0: `+
+		`day and month order is ambiguous`, err.Error())
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,181 @@
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Writers render rows preceded by an optional header (nil when there is none). Rows
+// shorter than the widest row or the header are padded with empty cells, so that the
+// output is rectangular.
+
+// WriteAligned renders rows as plain text with columns separated by two spaces and
+// padded to the width of their widest cell in display cells. Line breaks inside cells
+// are written as spaces. The output can be read back with ParseAligned or, when there
+// is a header, with ParseAlignedWithHeaderAt(lines, 0). Cells read back keep their
+// padding. Header with an empty cell (like the corner of a box table) is an error, the
+// header parser couldn't find the column; pass such header as the first row instead.
+func WriteAligned(w io.Writer, header []string, rows [][]string) error {
+	for i, name := range header {
+		if isWhiteSpace(singleLine(name)) {
+			return errors.Errorf(
+				"can't write aligned table: header cell %d is empty, write it as the first row", i)
+		}
+	}
+	all := withHeader(header, rows)
+	widths := make([]int, tableWidth(all))
+	for _, row := range all {
+		for i, cell := range row {
+			widths[i] = maxInt(widths[i], displayWidth(singleLine(cell)))
+		}
+	}
+	var b bytes.Buffer
+	for _, row := range all {
+		var line strings.Builder
+		for i, width := range widths {
+			cell := singleLine(cellAt(row, i))
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", width-displayWidth(cell)))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	return writeBuffer(w, &b, "can't write aligned table")
+}
+
+// WriteMarkdown renders rows as a pipe table. Markdown tables always have a header,
+// without one the first row is used. Pipes are escaped and line breaks inside cells
+// are written as spaces. The output can be read back with ParseMarkdown.
+func WriteMarkdown(w io.Writer, header []string, rows [][]string) error {
+	if header == nil && len(rows) > 0 {
+		header, rows = rows[0], rows[1:]
+	}
+	all := withHeader(header, rows)
+	n := tableWidth(all)
+	if n == 0 {
+		return nil
+	}
+	escaped := make([][]string, len(all))
+	widths := make([]int, n)
+	for y, row := range all {
+		escaped[y] = make([]string, n)
+		for i := range widths {
+			cell := strings.Replace(singleLine(cellAt(row, i)), "|", `\|`, -1)
+			escaped[y][i] = cell
+			widths[i] = maxInt(maxInt(widths[i], displayWidth(cell)), 3)
+		}
+	}
+	delimiter := make([]string, n)
+	for i, width := range widths {
+		delimiter[i] = strings.Repeat("-", width)
+	}
+	var b bytes.Buffer
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i, cell := range row {
+			b.WriteString(" " + cell + strings.Repeat(" ", widths[i]-displayWidth(cell)) + " |")
+		}
+		b.WriteByte('\n')
+	}
+	writeRow(escaped[0])
+	writeRow(delimiter)
+	for _, row := range escaped[1:] {
+		writeRow(row)
+	}
+	return writeBuffer(w, &b, "can't write markdown table")
+}
+
+// WriteCSV renders rows as RFC 4180 CSV with CRLF line endings. Header is written as
+// the first record. The output can be read back with encoding/csv or SniffCSV.
+func WriteCSV(w io.Writer, header []string, rows [][]string) error {
+	all := withHeader(header, rows)
+	n := tableWidth(all)
+	var b bytes.Buffer
+	c := csv.NewWriter(&b)
+	c.UseCRLF = true
+	for _, row := range all {
+		if n == 1 && cellAt(row, 0) == "" {
+			// an empty line would be skipped by readers
+			c.Flush()
+			b.WriteString("\"\"\r\n")
+			continue
+		}
+		record := make([]string, n)
+		copy(record, row)
+		if err := c.Write(record); err != nil {
+			return errors.Wrap(err, "can't write csv")
+		}
+	}
+	c.Flush()
+	if err := c.Error(); err != nil {
+		return errors.Wrap(err, "can't write csv")
+	}
+	return writeBuffer(w, &b, "can't write csv")
+}
+
+// WriteHTML renders rows as HTML table, the header is written as th cells in thead and
+// rows as td cells in tbody. The output can be read back with ParseHTMLTable, cells
+// are trimmed by the parser.
+func WriteHTML(w io.Writer, header []string, rows [][]string) error {
+	n := tableWidth(withHeader(header, rows))
+	var b bytes.Buffer
+	writeRow := func(row []string, tag string) {
+		b.WriteString("<tr>")
+		for i := 0; i < n; i++ {
+			b.WriteString("<" + tag + ">" + html.EscapeString(cellAt(row, i)) + "</" + tag + ">")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("<table>\n")
+	if header != nil {
+		b.WriteString("<thead>\n")
+		writeRow(header, "th")
+		b.WriteString("</thead>\n")
+	}
+	b.WriteString("<tbody>\n")
+	for _, row := range rows {
+		writeRow(row, "td")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return writeBuffer(w, &b, "can't write html table")
+}
+
+func writeBuffer(w io.Writer, b *bytes.Buffer, msg string) error {
+	_, err := w.Write(b.Bytes())
+	return errors.Wrap(err, msg)
+}
+
+func withHeader(header []string, rows [][]string) [][]string {
+	if header == nil {
+		return rows
+	}
+	return append([][]string{header}, rows...)
+}
+
+func tableWidth(rows [][]string) int {
+	var width int
+	for _, row := range rows {
+		width = maxInt(width, len(row))
+	}
+	return width
+}
+
+func cellAt(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// singleLine replaces line breaks with spaces
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,139 @@
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type writerSuite struct{ suite.Suite }
+
+func TestWriter(t *testing.T) { suite.Run(t, new(writerSuite)) }
+
+var writerHeader = []string{"Date", "Description", "Amount"}
+
+var writerRows = [][]string{
+	{"2018-02-01", "Coffee | tea", "4.50"},
+	{"2018-02-02", "東京 <hotel> & spa", ""},
+	{"2018-02-03", `quoted "text", comma`},
+}
+
+func (s *writerSuite) TestWriteAligned() {
+	var b bytes.Buffer
+	require.Nil(s.T(), WriteAligned(&b, writerHeader, writerRows))
+	require.Equal(s.T(), ""+
+		"Date        Description           Amount\n"+
+		"2018-02-01  Coffee | tea          4.50\n"+
+		"2018-02-02  東京 <hotel> & spa\n"+
+		"2018-02-03  quoted \"text\", comma\n", b.String())
+
+	t, err := ParseAlignedWithHeaderAt(strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n"), 0)
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), writerHeader, t.Columns())
+	var trimmed [][]string
+	for _, line := range t.Lines() {
+		trimmed = append(trimmed, trimSpace(line))
+	}
+	require.Equal(s.T(), [][]string{
+		{"2018-02-01", "Coffee | tea", "4.50"},
+		{"2018-02-02", "東京 <hotel> & spa", ""},
+		{"2018-02-03", `quoted "text", comma`, ""},
+	}, trimmed)
+}
+
+func (s *writerSuite) TestWriteAlignedWithEmptyHeaderCell() {
+	var b bytes.Buffer
+	err := WriteAligned(&b, []string{"", "Amount"}, [][]string{{"Total", "5"}})
+	require.EqualError(s.T(), err,
+		"can't write aligned table: header cell 0 is empty, write it as the first row")
+	require.Empty(s.T(), b.String())
+
+	// as the first row it's read back by ParseAligned
+	require.Nil(s.T(), WriteAligned(&b, nil, [][]string{{"", "Amount"}, {"Total", "5"}}))
+	require.Equal(s.T(), "       Amount\nTotal  5\n", b.String())
+	p, err := ParseAligned(strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n"), 2)
+	require.Nil(s.T(), err)
+	var trimmed [][]string
+	for _, line := range p.Lines() {
+		trimmed = append(trimmed, trimSpace(line))
+	}
+	require.Equal(s.T(), [][]string{{"", "Amount"}, {"Total", "5"}}, trimmed)
+}
+
+func (s *writerSuite) TestWriteMarkdown() {
+	var b bytes.Buffer
+	require.Nil(s.T(), WriteMarkdown(&b, writerHeader, writerRows))
+	require.Equal(s.T(), ""+
+		"| Date       | Description          | Amount |\n"+
+		"| ---------- | -------------------- | ------ |\n"+
+		"| 2018-02-01 | Coffee \\| tea        | 4.50   |\n"+
+		"| 2018-02-02 | 東京 <hotel> & spa   |        |\n"+
+		"| 2018-02-03 | quoted \"text\", comma |        |\n", b.String())
+
+	t, err := ParseMarkdown(strings.Split(b.String(), "\n"))
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), writerHeader, t.Columns())
+	require.Equal(s.T(), [][]string{
+		{"2018-02-01", "Coffee | tea", "4.50"},
+		{"2018-02-02", "東京 <hotel> & spa", ""},
+		{"2018-02-03", `quoted "text", comma`, ""},
+	}, t.Lines())
+
+	b.Reset()
+	require.Nil(s.T(), WriteMarkdown(&b, nil, [][]string{{"a"}, {"multi\nline"}}))
+	require.Equal(s.T(), "| a          |\n| ---------- |\n| multi line |\n", b.String())
+}
+
+func (s *writerSuite) TestWriteCSV() {
+	var b bytes.Buffer
+	require.Nil(s.T(), WriteCSV(&b, writerHeader, writerRows))
+	require.Equal(s.T(), ""+
+		"Date,Description,Amount\r\n"+
+		"2018-02-01,Coffee | tea,4.50\r\n"+
+		"2018-02-02,東京 <hotel> & spa,\r\n"+
+		"2018-02-03,\"quoted \"\"text\"\", comma\",\r\n", b.String())
+
+	records, err := csv.NewReader(&b).ReadAll()
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{
+		writerHeader,
+		{"2018-02-01", "Coffee | tea", "4.50"},
+		{"2018-02-02", "東京 <hotel> & spa", ""},
+		{"2018-02-03", `quoted "text", comma`, ""},
+	}, records)
+
+	b.Reset()
+	require.Nil(s.T(), WriteCSV(&b, nil, [][]string{{"a"}, {""}, {"two\nlines"}}))
+	records, err = csv.NewReader(&b).ReadAll()
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), [][]string{{"a"}, {""}, {"two\nlines"}}, records)
+}
+
+func (s *writerSuite) TestWriteHTML() {
+	var b bytes.Buffer
+	require.Nil(s.T(), WriteHTML(&b, writerHeader, writerRows))
+	require.Equal(s.T(), ""+
+		"<table>\n"+
+		"<thead>\n"+
+		"<tr><th>Date</th><th>Description</th><th>Amount</th></tr>\n"+
+		"</thead>\n"+
+		"<tbody>\n"+
+		"<tr><td>2018-02-01</td><td>Coffee | tea</td><td>4.50</td></tr>\n"+
+		"<tr><td>2018-02-02</td><td>東京 &lt;hotel&gt; &amp; spa</td><td></td></tr>\n"+
+		"<tr><td>2018-02-03</td><td>quoted &#34;text&#34;, comma</td><td></td></tr>\n"+
+		"</tbody>\n"+
+		"</table>\n", b.String())
+
+	t, err := ParseHTMLTable(b.String())
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), writerHeader, t.Table().Columns())
+	require.Equal(s.T(), [][]string{
+		{"2018-02-01", "Coffee | tea", "4.50"},
+		{"2018-02-02", "東京 <hotel> & spa", ""},
+		{"2018-02-03", `quoted "text", comma`, ""},
+	}, t.Table().Lines())
+}